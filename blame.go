@@ -0,0 +1,73 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BlameLine describes the last change of a line of a file.
+type BlameLine struct {
+	Line        int       `json:"line"`
+	Commit      string    `json:"commit"`
+	Author      string    `json:"author"`
+	AuthorEmail string    `json:"author_email"`
+	Date        time.Time `json:"date"`
+	Content     string    `json:"content"`
+	// Uncommitted is true for a line changed in the working tree.
+	Uncommitted bool `json:"uncommitted"`
+}
+
+// Blame returns the last change of each line of a file in the working tree.
+func (r *Repo) Blame(path string) ([]BlameLine, error) {
+	v, err := r.getRaw("blame", "--line-porcelain", "--", path)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to blame '%s'. %s", path, err)
+	}
+
+	lines := make([]BlameLine, 0)
+	var line *BlameLine
+	var authorTime int64
+	for _, text := range strings.Split(v, "\n") {
+		if line == nil {
+			// <sha> <original line> <final line> [<group lines>]
+			fields := strings.Fields(text)
+			if len(fields) < 3 {
+				continue
+			}
+			line = &BlameLine{Commit: fields[0], Uncommitted: fields[0] == ZeroID}
+			line.Line, _ = strconv.Atoi(fields[2])
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(text, "\t"):
+			line.Content = text[1:]
+			lines = append(lines, *line)
+			line = nil
+		case strings.HasPrefix(text, "author "):
+			line.Author = strings.TrimPrefix(text, "author ")
+		case strings.HasPrefix(text, "author-mail "):
+			line.AuthorEmail = strings.Trim(strings.TrimPrefix(text, "author-mail "), "<>")
+		case strings.HasPrefix(text, "author-time "):
+			authorTime, _ = strconv.ParseInt(strings.TrimPrefix(text, "author-time "), 10, 64)
+			line.Date = time.Unix(authorTime, 0)
+		case strings.HasPrefix(text, "author-tz "):
+			if tz, err := time.Parse("-0700", strings.TrimPrefix(text, "author-tz ")); err == nil {
+				line.Date = line.Date.In(tz.Location())
+			}
+		}
+	}
+	return lines, nil
+}
+
+// BlameJSON returns the result of Blame encoded in JSON.
+func (r *Repo) BlameJSON(path string) ([]byte, error) {
+	lines, err := r.Blame(path)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(lines)
+}
@@ -0,0 +1,50 @@
+package git
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestBlameJSON(t *testing.T) {
+	t.Log("Expecting BlameJSON to export the blame of a file.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "line1\nline2\n", "first")
+	first := runGit(t, "rev-parse", "HEAD")
+	os.Setenv("GIT_AUTHOR_DATE", "2017-05-04T10:20:30+02:00")
+	writeFile(t, "file", "line1\nchanged\n")
+	runGit(t, "commit", "-q", "-a", "-m", "second", "--author", "Other <other@example.com>")
+	os.Unsetenv("GIT_AUTHOR_DATE")
+	second := runGit(t, "rev-parse", "HEAD")
+	writeFile(t, "file", "line1\nchanged\nuncommitted\n")
+
+	// Run the function
+	t.Log("Running BlameJSON(\"file\")...")
+	data, err := BlameJSON("file")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	var lines []BlameLine
+	if err = json.Unmarshal(data, &lines); err != nil {
+		t.Fatalf("Expected valid JSON. Got %s.", err)
+	}
+	if v := len(lines); v != 3 {
+		t.Fatalf("Expected 3 lines. Got %d.", v)
+	}
+	if v := lines[0]; v.Line != 1 || v.Commit != first || v.Author != "Test User" || v.Content != "line1" || v.Uncommitted {
+		t.Errorf("Expected line 1 from %s by 'Test User'. Got %+v.", first, v)
+	}
+	if v := lines[1]; v.Line != 2 || v.Commit != second || v.AuthorEmail != "other@example.com" || v.Content != "changed" {
+		t.Errorf("Expected line 2 from %s by 'other@example.com'. Got %+v.", second, v)
+	}
+	if v := lines[1].Date.Format("2006-01-02T15:04:05-07:00"); v != "2017-05-04T10:20:30+02:00" {
+		t.Errorf("Expected line 2 date '2017-05-04T10:20:30+02:00'. Got '%s'.", v)
+	}
+	if v := lines[2]; !v.Uncommitted || v.Content != "uncommitted" {
+		t.Errorf("Expected line 3 to be uncommitted. Got %+v.", v)
+	}
+}
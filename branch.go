@@ -0,0 +1,483 @@
+package git
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BranchStatus contains the tracking status of a local branch.
+type BranchStatus struct {
+	Branch   string
+	Upstream string
+	Ahead    int
+	Behind   int
+	Gone     bool
+}
+
+// AllBranchesTracking returns the tracking status of every local branch in a single git call.
+func (r *Repo) AllBranchesTracking() ([]BranchStatus, error) {
+	v, err := r.Get("for-each-ref", "--format=%(refname:short) %(upstream:short) %(upstream:track)", "refs/heads")
+	if err != nil || v == "" {
+		return []BranchStatus{}, err
+	}
+
+	lines := strings.Split(v, "\n")
+	branches := make([]BranchStatus, 0, len(lines))
+	for _, line := range lines {
+		fields := strings.SplitN(line, " ", 3)
+		if fields[0] == "" {
+			continue
+		}
+		branch := BranchStatus{Branch: fields[0]}
+		if len(fields) > 1 {
+			branch.Upstream = fields[1]
+		}
+		if len(fields) > 2 {
+			branch.Ahead, branch.Behind, branch.Gone = parseTrack(fields[2])
+		}
+		branches = append(branches, branch)
+	}
+	return branches, nil
+}
+
+// parseTrack decodes the %(upstream:track) format, ie "[ahead 1, behind 2]" or "[gone]".
+func parseTrack(track string) (ahead, behind int, gone bool) {
+	track = strings.Trim(track, " []")
+	if track == "gone" {
+		return 0, 0, true
+	}
+
+	trackRE, _ := regexp.Compile(`^(ahead|behind) (\d+)$`)
+	for _, part := range strings.Split(track, ", ") {
+		m := trackRE.FindStringSubmatch(part)
+		if m == nil {
+			continue
+		}
+		count, _ := strconv.Atoi(m[2])
+		if m[1] == "ahead" {
+			ahead = count
+		} else {
+			behind = count
+		}
+	}
+	return
+}
+
+// SyncStatus returns the state of the current branch compared to its upstream, to be displayed:
+// "up to date", "ahead 2", "behind 1", "ahead 2, behind 1", "no upstream", or "upstream gone"
+// if the upstream branch does not exist anymore.
+func (r *Repo) SyncStatus() (string, error) {
+	v, err := r.Get("status", "--branch", "--porcelain=v2", "--untracked-files=no")
+	if err != nil {
+		return "", fmt.Errorf("Unable to get the branch status. %s", err)
+	}
+
+	upstream := false
+	for _, line := range strings.Split(v, "\n") {
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) >= 3 && fields[1] == "branch.upstream":
+			upstream = true
+		case len(fields) == 4 && fields[1] == "branch.ab":
+			ahead, _ := strconv.Atoi(strings.TrimPrefix(fields[2], "+"))
+			behind, _ := strconv.Atoi(strings.TrimPrefix(fields[3], "-"))
+			switch {
+			case ahead > 0 && behind > 0:
+				return fmt.Sprintf("ahead %d, behind %d", ahead, behind), nil
+			case ahead > 0:
+				return fmt.Sprintf("ahead %d", ahead), nil
+			case behind > 0:
+				return fmt.Sprintf("behind %d", behind), nil
+			}
+			return "up to date", nil
+		}
+	}
+	if upstream {
+		// git does not report branch.ab when the upstream does not exist.
+		return "upstream gone", nil
+	}
+	return "no upstream", nil
+}
+
+// PrunableBranches returns the local branches safe to delete, without deleting them: the branches
+// fully merged into base, and the branches which upstream does not exist anymore.
+// base and the current branch are never listed.
+func (r *Repo) PrunableBranches(base string) ([]string, error) {
+	v, err := r.Get("for-each-ref", "--merged="+base, "--format=%(refname:short)", "refs/heads")
+	if err != nil {
+		return nil, fmt.Errorf("Unable to list the branches merged into '%s'. %s", base, err)
+	}
+	merged := make(map[string]bool)
+	for _, branch := range strings.Split(v, "\n") {
+		merged[branch] = true
+	}
+
+	branches, err := r.AllBranchesTracking()
+	if err != nil {
+		return nil, err
+	}
+	current, _ := r.Get("symbolic-ref", "--short", "-q", "HEAD")
+	prunable := make([]string, 0)
+	for _, branch := range branches {
+		if branch.Branch == base || branch.Branch == current {
+			continue
+		}
+		if merged[branch.Branch] || branch.Gone {
+			prunable = append(prunable, branch.Branch)
+		}
+	}
+	return prunable, nil
+}
+
+// PruneMergedTrackingBranches removes local remote-tracking refs fully merged into base.
+// It returns the list of removed refs, formatted as <remote>/<branchName>
+func (r *Repo) PruneMergedTrackingBranches(base string) ([]string, error) {
+	v, err := r.Get("for-each-ref", "--merged="+base, "--format=%(refname) %(refname:short) %(symref)", "refs/remotes")
+	if err != nil || v == "" {
+		return []string{}, err
+	}
+
+	removed := make([]string, 0)
+	for _, line := range strings.Split(v, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			// Symbolic refs like <remote>/HEAD are not removed.
+			continue
+		}
+		if r.Do("update-ref", "-d", fields[0]) > 0 {
+			return removed, fmt.Errorf("Unable to remove the remote-tracking ref '%s'", fields[1])
+		}
+		removed = append(removed, fields[1])
+	}
+	return removed, nil
+}
+
+// CleanupTrackingRefs removes the remote-tracking refs of remote, ie refs/remotes/<remote>/*,
+// left after the remote is removed. It returns the number of refs removed.
+func (r *Repo) CleanupTrackingRefs(remote string) (int, error) {
+	v, err := r.Get("for-each-ref", "--format=%(refname) %(symref)", "refs/remotes/"+remote+"/")
+	if err != nil || v == "" {
+		return 0, err
+	}
+
+	updates := make([]RefUpdate, 0)
+	count := 0
+	for _, line := range strings.Split(v, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 {
+			// Deleting a symbolic ref like <remote>/HEAD would delete its target.
+			if r.Do("symbolic-ref", "--delete", fields[0]) > 0 {
+				return count, fmt.Errorf("Unable to remove the symbolic ref '%s'", fields[0])
+			}
+			count++
+			continue
+		}
+		updates = append(updates, RefUpdate{Ref: fields[0]})
+	}
+	if err := r.UpdateRefs(updates); err != nil {
+		return count, err
+	}
+	return count + len(updates), nil
+}
+
+// SetUpstreamBulk sets <remote>/<branch> as upstream of every local branch which name starts with prefix.
+// Branches without a corresponding remote branch are ignored.
+// It returns the number of branches configured.
+func (r *Repo) SetUpstreamBulk(prefix, remote string) (int, error) {
+	v, err := r.Get("for-each-ref", "--format=%(refname:short)", "refs/heads")
+	if err != nil || v == "" {
+		return 0, err
+	}
+	locals := strings.Split(v, "\n")
+
+	v, err = r.Get("for-each-ref", "--format=%(refname:short)", "refs/remotes/"+remote)
+	if err != nil {
+		return 0, err
+	}
+	remotes := make(map[string]bool)
+	for _, branch := range strings.Split(v, "\n") {
+		remotes[branch] = true
+	}
+
+	count := 0
+	for _, branch := range locals {
+		if !strings.HasPrefix(branch, prefix) || !remotes[remote+"/"+branch] {
+			continue
+		}
+		if r.Do("branch", "--set-upstream-to="+remote+"/"+branch, branch) > 0 {
+			return count, fmt.Errorf("Unable to set the upstream of '%s'", branch)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// UpstreamRewritten returns true if the last update of the upstream of branch was not a fast-forward,
+// ie the upstream history was rewritten, like after a forced push.
+// It relies on the reflog of the remote-tracking branch.
+func (r *Repo) UpstreamRewritten(branch string) (bool, error) {
+	upstream, err := r.Get("rev-parse", "--symbolic-full-name", branch+"@{upstream}")
+	if err != nil {
+		return false, fmt.Errorf("Unable to find the upstream of '%s'. %s", branch, err)
+	}
+	if upstream == "" {
+		return false, fmt.Errorf("Unable to find the upstream of '%s'. No upstream ref found", branch)
+	}
+
+	v, err := r.Get("reflog", "show", "-n", "2", "--format=%H", upstream, "--")
+	if err != nil {
+		return false, fmt.Errorf("Unable to read the reflog of '%s'. %s", upstream, err)
+	}
+	shas := strings.Split(v, "\n")
+	if len(shas) < 2 {
+		// Never updated since created.
+		return false, nil
+	}
+
+	fastForward, err := r.isAncestor(shas[1], shas[0])
+	if err != nil {
+		return false, err
+	}
+	return !fastForward, nil
+}
+
+// HaveDiverged returns true if a and b both have commits the other does not contain,
+// ie they can only be integrated by a merge or a rebase.
+func (r *Repo) HaveDiverged(a, b string) (bool, error) {
+	if ancestor, err := r.isAncestor(a, b); err != nil || ancestor {
+		return false, err
+	}
+	if ancestor, err := r.isAncestor(b, a); err != nil || ancestor {
+		return false, err
+	}
+	return true, nil
+}
+
+// isAncestor returns true if the commit ancestor is reachable from commit.
+func (r *Repo) isAncestor(ancestor, commit string) (bool, error) {
+	_, err := r.Get("merge-base", "--is-ancestor", ancestor, commit)
+	switch exitCode(err) {
+	case 0:
+		return true, nil
+	case 1:
+		return false, nil
+	}
+	return false, fmt.Errorf("Unable to check if '%s' is an ancestor of '%s'. %s", ancestor, commit, err)
+}
+
+// BranchActivity contains the date and author of the last commit of a branch.
+type BranchActivity struct {
+	Branch         string
+	LastCommitDate time.Time
+	LastAuthor     string
+}
+
+// BranchesByActivity returns the local branches, or the remote-tracking branches if remote is true,
+// sorted by last commit date, the most recent first.
+func (r *Repo) BranchesByActivity(remote bool) ([]BranchActivity, error) {
+	refs := "refs/heads"
+	if remote {
+		refs = "refs/remotes"
+	}
+	v, err := r.Get("for-each-ref", "--sort=-committerdate",
+		"--format=%(refname:short)%00%(committerdate:iso-strict)%00%(authorname)%00%(symref)", refs)
+	if err != nil || v == "" {
+		return []BranchActivity{}, err
+	}
+
+	branches := make([]BranchActivity, 0)
+	for _, line := range strings.Split(v, "\n") {
+		fields := strings.Split(line, "\x00")
+		if len(fields) != 4 || fields[3] != "" {
+			// Symbolic refs like <remote>/HEAD are ignored.
+			continue
+		}
+		date, err := time.Parse(time.RFC3339, fields[1])
+		if err != nil {
+			return branches, fmt.Errorf("Unable to parse the date of '%s'. %s", fields[0], err)
+		}
+		branches = append(branches, BranchActivity{Branch: fields[0], LastCommitDate: date, LastAuthor: fields[2]})
+	}
+	return branches, nil
+}
+
+// CheckoutTracking checks out branch. If the local branch does not exist, it is created
+// from <remote>/<branch>, with it as upstream.
+// If remote is empty, the default remote is used. See Repo.DefaultRemote.
+func (r *Repo) CheckoutTracking(remote, branch string) error {
+	if r.localBranchExists(branch) {
+		if r.Do("checkout", "-q", branch, "--") > 0 {
+			return fmt.Errorf("Unable to checkout '%s'", branch)
+		}
+		return nil
+	}
+
+	remote, err := r.resolveRemote(remote)
+	if err != nil {
+		return err
+	}
+	if r.Do("checkout", "-q", "-b", branch, "--track", remote+"/"+branch, "--") > 0 {
+		return fmt.Errorf("Unable to checkout '%s' from '%s/%s'", branch, remote, branch)
+	}
+	return nil
+}
+
+// CheckoutOptions defines how Checkout handles a branch which does not exist locally.
+type CheckoutOptions struct {
+	// Create creates the missing branch from StartPoint, HEAD if empty.
+	Create     bool
+	StartPoint string
+	// Track creates the missing branch from <Remote>/<name>, with it as upstream. See CheckoutTracking.
+	// If Remote is empty, the default remote is used.
+	Track  bool
+	Remote string
+}
+
+// CheckoutConflictError is returned when a checkout would overwrite local changes.
+type CheckoutConflictError struct {
+	Branch string
+	// Files are the files with local changes which the checkout would overwrite.
+	Files []string
+}
+
+// Error returns the branch and the files in conflict.
+func (e *CheckoutConflictError) Error() string {
+	return fmt.Sprintf("Unable to checkout '%s'. Local changes of %s would be overwritten", e.Branch, strings.Join(e.Files, ", "))
+}
+
+// CreateBranch creates the branch name from startPoint, HEAD if empty. The branch is not checked out.
+func (r *Repo) CreateBranch(name, startPoint string) error {
+	opts := []string{"branch", name}
+	if startPoint != "" {
+		opts = append(opts, startPoint)
+	}
+	if r.Do(opts...) > 0 {
+		return fmt.Errorf("Unable to create the branch '%s'", name)
+	}
+	return nil
+}
+
+// Checkout checks out the local branch name. If it does not exist, it is created as defined by opts,
+// or an error is returned. A *CheckoutConflictError is returned if local changes would be overwritten.
+func (r *Repo) Checkout(name string, opts CheckoutOptions) error {
+	exists := r.localBranchExists(name)
+	switch {
+	case !exists && opts.Track:
+		return r.CheckoutTracking(opts.Remote, name)
+	case !exists && !opts.Create:
+		return fmt.Errorf("Unable to checkout '%s'. The branch does not exist", name)
+	case !exists:
+		checkoutOpts := []string{"checkout", "-q", "-b", name}
+		if opts.StartPoint != "" {
+			checkoutOpts = append(checkoutOpts, opts.StartPoint)
+		}
+		if r.Do(append(checkoutOpts, "--")...) > 0 {
+			return fmt.Errorf("Unable to create the branch '%s'", name)
+		}
+		return nil
+	}
+
+	files, err := r.CheckoutWouldConflict(name)
+	if err != nil {
+		return err
+	}
+	if len(files) > 0 {
+		return &CheckoutConflictError{Branch: name, Files: files}
+	}
+	if r.Do("checkout", "-q", name, "--") > 0 {
+		return fmt.Errorf("Unable to checkout '%s'", name)
+	}
+	return nil
+}
+
+// DeleteBranch removes the local branch name. Unless force is true, the branch must be merged
+// in its upstream, or in HEAD if it has no upstream.
+func (r *Repo) DeleteBranch(name string, force bool) error {
+	mode := "-d"
+	if force {
+		mode = "-D"
+	}
+	if r.Do("branch", mode, name) > 0 {
+		return fmt.Errorf("Unable to delete the branch '%s'", name)
+	}
+	return nil
+}
+
+// SwitchBranch checks out the existing local branch name.
+// If autoStash is true, local changes, including untracked files, are stashed before the checkout
+// and applied again after it, as unstaged changes. If they conflict with the branch, the error returned satisfies
+// IsMergeConflict and the changes are kept in the stash.
+// Otherwise a *CheckoutConflictError is returned if local changes would be overwritten.
+func (r *Repo) SwitchBranch(name string, autoStash bool) error {
+	if !autoStash {
+		return r.Checkout(name, CheckoutOptions{})
+	}
+	if !r.localBranchExists(name) {
+		return fmt.Errorf("Unable to switch to '%s'. The branch does not exist", name)
+	}
+
+	status := r.GetStatus()
+	if status.Err != nil {
+		return fmt.Errorf("Unable to get the local changes. %s", status.Err)
+	}
+	if status.IsClean() {
+		return r.Checkout(name, CheckoutOptions{})
+	}
+
+	// git stash succeeds without creating an entry if nothing can be stashed, like a modified submodule.
+	previous, _ := r.Get("rev-parse", "-q", "--verify", "refs/stash")
+	if r.Do("stash", "push", "-q", "--include-untracked", "-m", "SwitchBranch to "+name) > 0 {
+		return fmt.Errorf("Unable to stash the local changes")
+	}
+	if stash, _ := r.Get("rev-parse", "-q", "--verify", "refs/stash"); stash == previous {
+		return r.Checkout(name, CheckoutOptions{})
+	}
+	err := r.Checkout(name, CheckoutOptions{})
+	if popErr := r.doError("stash", "pop", "-q"); popErr != nil {
+		if err != nil {
+			return fmt.Errorf("%s. The local changes are kept in the stash. %w", err, popErr)
+		}
+		return fmt.Errorf("Unable to apply the local changes on '%s'. They are kept in the stash. %w", name, popErr)
+	}
+	return err
+}
+
+// EnsureBranchTracked sets <remote>/<branch> as upstream of the local branch, if it has no upstream yet.
+// An existing upstream is kept, even on another remote. The remote-tracking branch must exist, see Fetch.
+// If remote is empty, the default remote is used. See Repo.DefaultRemote.
+func (r *Repo) EnsureBranchTracked(branch, remote string) error {
+	if _, err := r.Get("rev-parse", "--verify", "-q", "--symbolic-full-name", branch+"@{upstream}"); err == nil {
+		return nil
+	}
+
+	remote, err := r.resolveRemote(remote)
+	if err != nil {
+		return err
+	}
+	if r.Do("branch", "--set-upstream-to="+remote+"/"+branch, branch) > 0 {
+		return fmt.Errorf("Unable to set '%s/%s' as upstream of '%s'", remote, branch, branch)
+	}
+	return nil
+}
+
+// localBranchExists returns true if refs/heads/<branch> exists.
+func (r *Repo) localBranchExists(branch string) bool {
+	_, err := r.Get("show-ref", "--verify", "-q", "refs/heads/"+branch)
+	return err == nil
+}
+
+// IsProtectedBranch returns true if branch matches one of the glob patterns, like "master" or "release/*".
+// "*" matches any sequence of characters except "/", and "?" any single character except "/".
+// Invalid patterns never match.
+func IsProtectedBranch(branch string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, branch); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
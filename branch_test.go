@@ -0,0 +1,698 @@
+package git
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestParseTrack(t *testing.T) {
+	t.Log("Expecting parseTrack to decode upstream track information.")
+	tests := []struct {
+		track  string
+		ahead  int
+		behind int
+		gone   bool
+	}{
+		{"", 0, 0, false},
+		{"[ahead 2]", 2, 0, false},
+		{"[behind 3]", 0, 3, false},
+		{"[ahead 1, behind 4]", 1, 4, false},
+		{"[gone]", 0, 0, true},
+	}
+
+	for _, test := range tests {
+		ahead, behind, gone := parseTrack(test.track)
+		if ahead != test.ahead || behind != test.behind || gone != test.gone {
+			t.Errorf("Expected parseTrack(%q) to return %d, %d, %t. Got %d, %d, %t.",
+				test.track, test.ahead, test.behind, test.gone, ahead, behind, gone)
+		}
+	}
+}
+
+func TestAllBranchesTracking(t *testing.T) {
+	t.Log("Expecting AllBranchesTracking to report every local branch state.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	runGit(t, "branch", "behind")
+	runGit(t, "branch", "--set-upstream-to", "master", "behind")
+	commitFile(t, "file", "2", "second")
+	runGit(t, "checkout", "-q", "-b", "ahead")
+	runGit(t, "branch", "--set-upstream-to", "master", "ahead")
+	commitFile(t, "file", "3", "third")
+	runGit(t, "remote", "add", "origin", "/nonexistent")
+	runGit(t, "branch", "gone")
+	runGit(t, "config", "branch.gone.remote", "origin")
+	runGit(t, "config", "branch.gone.merge", "refs/heads/gone")
+
+	// Run the function
+	t.Log("Running AllBranchesTracking()...")
+	branches, err := AllBranchesTracking()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	expected := map[string]BranchStatus{
+		"ahead":  {Branch: "ahead", Upstream: "master", Ahead: 1},
+		"behind": {Branch: "behind", Upstream: "master", Behind: 1},
+		"gone":   {Branch: "gone", Upstream: "origin/gone", Gone: true},
+		"master": {Branch: "master"},
+	}
+	if v := len(branches); v != len(expected) {
+		t.Errorf("Expected %d branches. Got %d.", len(expected), v)
+	}
+	for _, branch := range branches {
+		if v, found := expected[branch.Branch]; !found {
+			t.Errorf("Unexpected branch '%s'.", branch.Branch)
+		} else if v != branch {
+			t.Errorf("Expected %+v. Got %+v.", v, branch)
+		}
+	}
+}
+
+func TestPruneMergedTrackingBranches(t *testing.T) {
+	t.Log("Expecting PruneMergedTrackingBranches to remove only merged remote-tracking refs.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	runGit(t, "update-ref", "refs/remotes/origin/merged", "HEAD")
+	runGit(t, "checkout", "-q", "-b", "feature")
+	commitFile(t, "file", "2", "second")
+	runGit(t, "update-ref", "refs/remotes/origin/unmerged", "HEAD")
+	runGit(t, "checkout", "-q", "master")
+
+	// Run the function
+	t.Log("Running PruneMergedTrackingBranches(\"master\")...")
+	removed, err := PruneMergedTrackingBranches("master")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v := len(removed); v != 1 {
+		t.Errorf("Expected 1 ref removed. Got %d.", v)
+	} else if removed[0] != "origin/merged" {
+		t.Errorf("Expected 'origin/merged' to be removed. Got '%s'.", removed[0])
+	}
+	if v := runGit(t, "for-each-ref", "--format=%(refname:short)", "refs/remotes"); v != "origin/unmerged" {
+		t.Errorf("Expected only 'origin/unmerged' to be kept. Got '%s'.", v)
+	}
+}
+
+func TestSetUpstreamBulk(t *testing.T) {
+	t.Log("Expecting SetUpstreamBulk to set upstream of matching branches.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	runGit(t, "remote", "add", "origin", "/nonexistent")
+	for _, branch := range []string{"release/1.0", "release/2.0", "release/local", "feature"} {
+		runGit(t, "branch", branch)
+		if branch != "release/local" {
+			runGit(t, "update-ref", "refs/remotes/origin/"+branch, "HEAD")
+		}
+	}
+
+	// Run the function
+	t.Log("Running SetUpstreamBulk(\"release/\", \"origin\")...")
+	count, err := SetUpstreamBulk("release/", "origin")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 branches configured. Got %d.", count)
+	}
+	expected := map[string]string{
+		"release/1.0":   "origin/release/1.0",
+		"release/2.0":   "origin/release/2.0",
+		"release/local": "",
+		"feature":       "",
+	}
+	for branch, upstream := range expected {
+		if v := runGit(t, "for-each-ref", "--format=%(upstream:short)", "refs/heads/"+branch); v != upstream {
+			t.Errorf("Expected '%s' upstream to be '%s'. Got '%s'.", branch, upstream, v)
+		}
+	}
+}
+
+func TestUpstreamRewritten(t *testing.T) {
+	t.Log("Expecting UpstreamRewritten to detect a forced update of the upstream.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	base := runGit(t, "rev-parse", "HEAD")
+	runGit(t, "remote", "add", "origin", "/nonexistent")
+	runGit(t, "update-ref", "--create-reflog", "refs/remotes/origin/master", base)
+	runGit(t, "branch", "--set-upstream-to", "origin/master")
+	commitFile(t, "file", "2", "second")
+	forward := runGit(t, "rev-parse", "HEAD")
+	runGit(t, "update-ref", "-m", "fetch: fast-forward", "refs/remotes/origin/master", forward)
+
+	// Run the function
+	t.Log("Running UpstreamRewritten(\"master\") after a fast-forward...")
+	rewritten, err := UpstreamRewritten("master")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if rewritten {
+		t.Errorf("Expected the upstream not to be rewritten. Got rewritten.")
+	}
+
+	runGit(t, "checkout", "-q", "-b", "rewrite", base)
+	commitFile(t, "file", "rewritten", "rewritten")
+	rewrite := runGit(t, "rev-parse", "HEAD")
+	runGit(t, "update-ref", "-m", "fetch: forced-update", "refs/remotes/origin/master", rewrite)
+
+	// Run the function
+	t.Log("Running UpstreamRewritten(\"master\") after a forced update...")
+	rewritten, err = UpstreamRewritten("master")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if !rewritten {
+		t.Errorf("Expected the upstream to be rewritten. Got not rewritten.")
+	}
+}
+
+func TestHaveDiverged(t *testing.T) {
+	t.Log("Expecting HaveDiverged to detect diverged branches.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	runGit(t, "branch", "equal")
+	runGit(t, "branch", "behind")
+	runGit(t, "branch", "diverged")
+	commitFile(t, "file", "2", "second")
+	runGit(t, "checkout", "-q", "diverged")
+	commitFile(t, "other", "1", "other")
+	runGit(t, "checkout", "-q", "master")
+	runGit(t, "branch", "-f", "equal")
+
+	tests := []struct {
+		a, b     string
+		diverged bool
+	}{
+		{"master", "diverged", true},
+		{"master", "behind", false},
+		{"behind", "master", false},
+		{"master", "equal", false},
+	}
+	for _, test := range tests {
+		// Run the function
+		t.Logf("Running HaveDiverged(%q, %q)...", test.a, test.b)
+		diverged, err := HaveDiverged(test.a, test.b)
+
+		// Test the result
+		if err != nil {
+			t.Errorf("Expected no error. Got %s.", err)
+		} else if diverged != test.diverged {
+			t.Errorf("Expected '%s' and '%s' diverged to be %t. Got %t.", test.a, test.b, test.diverged, diverged)
+		}
+	}
+}
+
+func TestBranchesByActivity(t *testing.T) {
+	t.Log("Expecting BranchesByActivity to sort branches by last commit date.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitAt := func(file, date string) {
+		os.Setenv("GIT_COMMITTER_DATE", date)
+		defer os.Unsetenv("GIT_COMMITTER_DATE")
+		commitFile(t, file, "1", file)
+	}
+	commitAt("old", "2017-01-01T00:00:00Z")
+	runGit(t, "branch", "old")
+	commitAt("master", "2017-02-01T00:00:00Z")
+	runGit(t, "checkout", "-q", "-b", "recent")
+	commitAt("recent", "2017-03-01T00:00:00Z")
+	runGit(t, "update-ref", "refs/remotes/origin/recent", "recent")
+	runGit(t, "symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/recent")
+
+	// Run the function
+	t.Log("Running BranchesByActivity(false)...")
+	branches, err := BranchesByActivity(false)
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	expected := []string{"recent", "master", "old"}
+	if v := len(branches); v != len(expected) {
+		t.Fatalf("Expected %d branches. Got %d.", len(expected), v)
+	}
+	for i, branch := range branches {
+		if branch.Branch != expected[i] {
+			t.Errorf("Expected branch %d to be '%s'. Got '%s'.", i, expected[i], branch.Branch)
+		}
+		if branch.LastAuthor != "Test User" {
+			t.Errorf("Expected the last author to be 'Test User'. Got '%s'.", branch.LastAuthor)
+		}
+	}
+	if v := branches[0].LastCommitDate.UTC().Format("2006-01-02"); v != "2017-03-01" {
+		t.Errorf("Expected 'recent' last commit date to be 2017-03-01. Got %s.", v)
+	}
+
+	// Run the function
+	t.Log("Running BranchesByActivity(true)...")
+	if branches, err = BranchesByActivity(true); err != nil {
+		t.Errorf("Expected no error. Got %s.", err)
+	} else if len(branches) != 1 || branches[0].Branch != "origin/recent" {
+		t.Errorf("Expected ['origin/recent']. Got %+v.", branches)
+	}
+}
+
+func TestIsProtectedBranch(t *testing.T) {
+	t.Log("Expecting IsProtectedBranch to match branches against glob patterns.")
+	patterns := []string{"master", "release/*", "hotfix-?", "[invalid"}
+	tests := map[string]bool{
+		"master":          true,
+		"release/1.0":     true,
+		"hotfix-1":        true,
+		"master2":         false,
+		"release":         false,
+		"release/1.0/fix": false,
+		"hotfix-10":       false,
+		"feature/master":  false,
+	}
+
+	for branch, expected := range tests {
+		// Run the function
+		v := IsProtectedBranch(branch, patterns)
+
+		// Test the result
+		if v != expected {
+			t.Errorf("Expected '%s' protected to be %t. Got %t.", branch, expected, v)
+		}
+	}
+	if IsProtectedBranch("master", nil) {
+		t.Errorf("Expected no branch protected without patterns. Got 'master' protected.")
+	}
+}
+
+func TestCleanupTrackingRefs(t *testing.T) {
+	t.Log("Expecting CleanupTrackingRefs to remove the tracking refs of a removed remote.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	runGit(t, "remote", "add", "origin", "/nonexistent")
+	for _, ref := range []string{"origin/master", "origin/feature/one", "origin-fork/master", "upstream/master"} {
+		runGit(t, "update-ref", "refs/remotes/"+ref, "HEAD")
+	}
+	runGit(t, "symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/master")
+	// Removing the remote configuration only keeps its tracking refs.
+	runGit(t, "config", "--remove-section", "remote.origin")
+
+	// Run the function
+	t.Log("Running CleanupTrackingRefs(\"origin\")...")
+	count, err := CleanupTrackingRefs("origin")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 refs removed. Got %d.", count)
+	}
+	if v := runGit(t, "for-each-ref", "--format=%(refname:short)", "refs/remotes"); v != "origin-fork/master\nupstream/master" {
+		t.Errorf("Expected only the other remotes refs to be kept. Got %q.", v)
+	}
+}
+
+func TestSyncStatus(t *testing.T) {
+	t.Log("Expecting SyncStatus to describe the current branch compared to its upstream.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	base := runGit(t, "rev-parse", "HEAD")
+	commitFile(t, "file", "2", "second")
+	commitFile(t, "file", "3", "third")
+	runGit(t, "branch", "synced")
+	runGit(t, "branch", "behind", base)
+	runGit(t, "checkout", "-q", "-b", "ahead")
+	commitFile(t, "file", "4", "fourth")
+	runGit(t, "checkout", "-q", "-b", "diverged", base)
+	commitFile(t, "other", "1", "other")
+	runGit(t, "branch", "none")
+	runGit(t, "branch", "gone")
+	for _, branch := range []string{"synced", "behind", "ahead", "diverged"} {
+		runGit(t, "branch", "-q", "--set-upstream-to", "master", branch)
+	}
+	runGit(t, "remote", "add", "origin", "/nonexistent")
+	runGit(t, "config", "branch.gone.remote", "origin")
+	runGit(t, "config", "branch.gone.merge", "refs/heads/gone")
+
+	tests := map[string]string{
+		"synced":   "up to date",
+		"ahead":    "ahead 1",
+		"behind":   "behind 2",
+		"diverged": "ahead 1, behind 2",
+		"none":     "no upstream",
+		"gone":     "upstream gone",
+	}
+	for branch, expected := range tests {
+		runGit(t, "checkout", "-q", branch)
+
+		// Run the function
+		v, err := SyncStatus()
+
+		// Test the result
+		if err != nil {
+			t.Errorf("Expected no error. Got %s.", err)
+		} else if v != expected {
+			t.Errorf("Expected '%s' sync status to be '%s'. Got '%s'.", branch, expected, v)
+		}
+	}
+}
+
+func TestCheckoutTracking(t *testing.T) {
+	t.Log("Expecting CheckoutTracking to create a local branch tracking the remote one.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	// The remote is not in the working tree, which is replaced by the checkout.
+	remoteDir, err := ioutil.TempDir("", "go-git-test")
+	if err != nil {
+		t.Fatalf("Unable to create a temporary directory. %s", err)
+	}
+	defer os.RemoveAll(remoteDir)
+	initRemoteRepo(t, remoteDir)
+	runGitIn(t, remoteDir, "branch", "feature")
+	runGit(t, "remote", "add", "origin", remoteDir)
+	runGit(t, "fetch", "-q", "origin")
+	runGit(t, "checkout", "-q", "-b", "master", "origin/master")
+
+	// Run the function
+	t.Log("Running CheckoutTracking(\"origin\", \"feature\")...")
+	err = CheckoutTracking("origin", "feature")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v := runGit(t, "rev-parse", "--abbrev-ref", "HEAD"); v != "feature" {
+		t.Errorf("Expected 'feature' to be checked out. Got '%s'.", v)
+	}
+	if v := runGit(t, "rev-parse", "--abbrev-ref", "feature@{upstream}"); v != "origin/feature" {
+		t.Errorf("Expected 'feature' to track 'origin/feature'. Got '%s'.", v)
+	}
+
+	// Run the function
+	t.Log("Running CheckoutTracking(\"origin\", \"master\") on an existing branch...")
+	err = CheckoutTracking("origin", "master")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v := runGit(t, "rev-parse", "--abbrev-ref", "HEAD"); v != "master" {
+		t.Errorf("Expected 'master' to be checked out. Got '%s'.", v)
+	}
+
+	// Run the function
+	t.Log("Running CheckoutTracking(\"origin\", \"unknown\")...")
+	if err = CheckoutTracking("origin", "unknown"); err == nil {
+		t.Errorf("Expected an error. Got none.")
+	}
+}
+
+func TestPrunableBranches(t *testing.T) {
+	t.Log("Expecting PrunableBranches to list the merged branches and the branches with a gone upstream.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	runGit(t, "remote", "add", "origin", "/nonexistent")
+	runGit(t, "branch", "merged")
+	runGit(t, "checkout", "-q", "-b", "gone")
+	commitFile(t, "gone", "1", "gone")
+	runGit(t, "config", "branch.gone.remote", "origin")
+	runGit(t, "config", "branch.gone.merge", "refs/heads/gone")
+	runGit(t, "checkout", "-q", "-b", "active", "master")
+	commitFile(t, "active", "1", "active")
+	runGit(t, "update-ref", "refs/remotes/origin/active", "HEAD")
+	runGit(t, "branch", "--set-upstream-to=origin/active")
+	runGit(t, "checkout", "-q", "-b", "current", "master")
+
+	// Run the function
+	t.Log("Running PrunableBranches(\"master\")...")
+	branches, err := PrunableBranches("master")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if expected := []string{"gone", "merged"}; !reflect.DeepEqual(branches, expected) {
+		t.Errorf("Expected %q. Got %q.", expected, branches)
+	}
+	if v := runGit(t, "branch", "--list", "merged", "gone"); v == "" {
+		t.Errorf("Expected the branches to be kept. Got '%s'.", v)
+	}
+}
+
+func TestEnsureBranchTracked(t *testing.T) {
+	t.Log("Expecting EnsureBranchTracked to set the upstream of a branch only if missing.")
+	dir, done := initTestRepo(t)
+	defer done()
+
+	remoteDir := dir + "/remote"
+	initRemoteRepo(t, remoteDir)
+	runGitIn(t, remoteDir, "branch", "feature")
+	runGit(t, "remote", "add", "origin", remoteDir)
+	runGit(t, "fetch", "-q", "origin")
+	commitFile(t, "file", "1", "first")
+	runGit(t, "branch", "feature")
+	runGit(t, "branch", "other", "--track", "origin/master")
+
+	// Run the function
+	t.Log("Running EnsureBranchTracked(\"feature\", \"\")...")
+	err := EnsureBranchTracked("feature", "")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v := runGit(t, "rev-parse", "--abbrev-ref", "feature@{upstream}"); v != "origin/feature" {
+		t.Errorf("Expected 'feature' to track 'origin/feature'. Got '%s'.", v)
+	}
+
+	// Run the function
+	t.Log("Running EnsureBranchTracked(\"other\", \"origin\") on a tracked branch...")
+	err = EnsureBranchTracked("other", "origin")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v := runGit(t, "rev-parse", "--abbrev-ref", "other@{upstream}"); v != "origin/master" {
+		t.Errorf("Expected 'other' to keep tracking 'origin/master'. Got '%s'.", v)
+	}
+
+	runGit(t, "branch", "-q", "-m", "master", "local")
+
+	// Run the function
+	t.Log("Running EnsureBranchTracked(\"local\", \"origin\") without remote branch...")
+	err = EnsureBranchTracked("local", "origin")
+
+	// Test the result
+	if err == nil {
+		t.Errorf("Expected an error. Got none.")
+	}
+}
+
+func TestBranchLifecycle(t *testing.T) {
+	t.Log("Expecting CreateBranch, Checkout and DeleteBranch to manage local branches.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	first := runGit(t, "rev-parse", "HEAD")
+	commitFile(t, "file", "2", "second")
+
+	// Run the function
+	t.Log("Running CreateBranch(\"old\", \"HEAD~1\")...")
+	err := CreateBranch("old", "HEAD~1")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v := runGit(t, "rev-parse", "old"); v != first {
+		t.Errorf("Expected 'old' to start from %s. Got %s.", first, v)
+	}
+	if err = CreateBranch("old", ""); err == nil {
+		t.Errorf("Expected an error for an existing branch. Got none.")
+	}
+
+	tests := []struct {
+		name     string
+		opts     CheckoutOptions
+		expected string
+		fails    bool
+	}{
+		{"old", CheckoutOptions{}, "old", false},
+		{"missing", CheckoutOptions{}, "old", true},
+		{"new", CheckoutOptions{Create: true, StartPoint: "master"}, "new", false},
+		{"master", CheckoutOptions{Create: true}, "master", false},
+	}
+	for _, test := range tests {
+		// Run the function
+		t.Logf("Running Checkout(\"%s\", %+v)...", test.name, test.opts)
+		err := Checkout(test.name, test.opts)
+
+		// Test the result
+		if (err != nil) != test.fails {
+			t.Errorf("Expected an error %t. Got %v.", test.fails, err)
+		}
+		if v := runGit(t, "rev-parse", "--abbrev-ref", "HEAD"); v != test.expected {
+			t.Errorf("Expected '%s' to be checked out. Got '%s'.", test.expected, v)
+		}
+	}
+	if v, expected := runGit(t, "rev-parse", "new"), runGit(t, "rev-parse", "master"); v != expected {
+		t.Errorf("Expected 'new' to start from master %s. Got %s.", expected, v)
+	}
+
+	writeFile(t, "file", "local")
+
+	// Run the function
+	t.Log("Running Checkout(\"old\", ...) with local changes...")
+	err = Checkout("old", CheckoutOptions{})
+
+	// Test the result
+	var conflict *CheckoutConflictError
+	if !errors.As(err, &conflict) || conflict.Branch != "old" || !reflect.DeepEqual(conflict.Files, []string{"file"}) {
+		t.Errorf("Expected a CheckoutConflictError on 'file'. Got %v.", err)
+	}
+
+	runGit(t, "checkout", "-q", "--", "file")
+	runGit(t, "checkout", "-q", "new")
+	commitFile(t, "new", "1", "unmerged")
+	runGit(t, "checkout", "-q", "master")
+
+	// Run the function
+	t.Log("Running DeleteBranch(\"new\", false) on an unmerged branch...")
+	if err = DeleteBranch("new", false); err == nil {
+		t.Errorf("Expected an error. Got none.")
+	}
+	t.Log("Running DeleteBranch(\"new\", true)...")
+	err = DeleteBranch("new", true)
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v := runGit(t, "branch", "--list", "new"); v != "" {
+		t.Errorf("Expected 'new' to be deleted. Got '%s'.", v)
+	}
+}
+
+func TestSwitchBranch(t *testing.T) {
+	t.Log("Expecting SwitchBranch to keep the local changes with autoStash.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	commitFile(t, "conflict", "1", "second")
+	runGit(t, "branch", "feature")
+	commitFile(t, "file", "2", "third")
+	writeFile(t, "file", "local")
+	writeFile(t, "untracked", "1")
+
+	// Run the function
+	t.Log("Running SwitchBranch(\"feature\", false)...")
+	err := SwitchBranch("feature", false)
+
+	// Test the result
+	var conflict *CheckoutConflictError
+	if !errors.As(err, &conflict) {
+		t.Errorf("Expected a CheckoutConflictError. Got %v.", err)
+	}
+
+	// Run the function
+	t.Log("Running SwitchBranch(\"feature\", true)...")
+	err = SwitchBranch("feature", true)
+
+	// Test the result
+	if !IsMergeConflict(err) {
+		t.Errorf("Expected the local changes to conflict. Got %v.", err)
+	}
+	if v := runGit(t, "rev-parse", "--abbrev-ref", "HEAD"); v != "feature" {
+		t.Errorf("Expected 'feature' to be checked out. Got '%s'.", v)
+	}
+	if v := runGit(t, "stash", "list"); v == "" {
+		t.Errorf("Expected the local changes to be kept in the stash.")
+	}
+
+	runGit(t, "checkout", "-q", "-f", "master")
+	runGit(t, "clean", "-q", "-f")
+	runGit(t, "stash", "drop", "-q")
+	writeFile(t, "conflict", "local")
+	writeFile(t, "untracked", "1")
+
+	// Run the function
+	t.Log("Running SwitchBranch(\"feature\", true) without conflict...")
+	err = SwitchBranch("feature", true)
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v := runGit(t, "status", "--porcelain"); v != "M conflict\n?? untracked" {
+		t.Errorf("Expected the local changes on 'feature'. Got '%s'.", v)
+	}
+	if v := runGit(t, "stash", "list"); v != "" {
+		t.Errorf("Expected the stash to be empty. Got '%s'.", v)
+	}
+}
+
+func TestSwitchBranchNothingStashed(t *testing.T) {
+	t.Log("Expecting SwitchBranch to keep the existing stash entries when git stashes nothing.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	subDir, err := ioutil.TempDir("", "go-git-test")
+	if err != nil {
+		t.Fatalf("Unable to create a temporary directory. %s", err)
+	}
+	defer os.RemoveAll(subDir)
+	initRemoteRepo(t, subDir)
+
+	commitFile(t, "file", "1", "first")
+	writeFile(t, "file", "stashed")
+	runGit(t, "stash", "push", "-q", "-m", "existing")
+	runGit(t, "-c", "protocol.file.allow=always", "submodule", "add", "-q", subDir, "sub")
+	runGit(t, "commit", "-q", "-m", "submodule")
+	runGit(t, "branch", "feature")
+	// Changes in a submodule are not stashed.
+	writeFile(t, "sub/untracked", "1")
+
+	// Run the function
+	err = SwitchBranch("feature", true)
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v := runGit(t, "rev-parse", "--abbrev-ref", "HEAD"); v != "feature" {
+		t.Errorf("Expected 'feature' to be checked out. Got '%s'.", v)
+	}
+	if v := runGit(t, "stash", "list"); v != "stash@{0}: On master: existing" {
+		t.Errorf("Expected the existing stash entry to be kept. Got '%s'.", v)
+	}
+}
@@ -0,0 +1,63 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CreateBundle writes the refs given, like branch or tag names, and their history in the bundle file outputPath.
+// Without refs, all refs are bundled. See CreateBundleSince for an incremental bundle.
+func (r *Repo) CreateBundle(outputPath string, refs ...string) error {
+	return r.CreateBundleSince(outputPath, "", refs...)
+}
+
+// CreateBundleSince writes the refs given in the bundle file outputPath, like CreateBundle,
+// without the commits reachable from since. The repository importing the bundle must already have since.
+// An empty since bundles the whole history.
+func (r *Repo) CreateBundleSince(outputPath, since string, refs ...string) error {
+	opts := []string{"bundle", "create", "-q", outputPath}
+	if len(refs) == 0 {
+		opts = append(opts, "--all")
+	}
+	opts = append(opts, refs...)
+	if since != "" {
+		opts = append(opts, "^"+since)
+	}
+	if r.Do(opts...) > 0 {
+		return fmt.Errorf("Unable to create the bundle '%s'", outputPath)
+	}
+	return nil
+}
+
+// VerifyBundle returns an error if path is not a valid bundle file, or if the repository misses
+// the commits the bundle requires.
+func (r *Repo) VerifyBundle(path string) error {
+	out, err := r.combinedOutputCommand(r.command("bundle", "verify", "-q", path))
+	if err != nil {
+		return fmt.Errorf("Invalid bundle '%s'. %s %s", path, err, strings.Trim(string(out), " \n"))
+	}
+	return nil
+}
+
+// CloneFromBundle clones the bundle file bundlePath in destPath and returns the new repository.
+// The bundle is checked first. It must contain the whole history, not be incremental.
+// The origin remote of the new repository is the bundle file.
+func CloneFromBundle(bundlePath, destPath string) (*Repo, error) {
+	// bundle verify needs a repository, list-heads only checks the bundle format.
+	if out, err := defaultRepo.combinedOutputCommand(defaultRepo.command("bundle", "list-heads", bundlePath)); err != nil {
+		return nil, fmt.Errorf("Invalid bundle '%s'. %s %s", bundlePath, err, strings.Trim(string(out), " \n"))
+	}
+	return Clone(bundlePath, destPath, CloneOptions{})
+}
+
+// FetchFromBundle imports the branches and tags of the bundle file bundlePath, checked first.
+// Branches are stored as refs/remotes/bundle/<branch>.
+func (r *Repo) FetchFromBundle(bundlePath string) error {
+	if err := r.VerifyBundle(bundlePath); err != nil {
+		return err
+	}
+	if r.Do("fetch", "--tags", bundlePath, "+refs/heads/*:refs/remotes/bundle/*") > 0 {
+		return fmt.Errorf("Unable to fetch the bundle '%s'", bundlePath)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package git
+
+import (
+	"os"
+	"testing"
+)
+
+func TestCreateBundle(t *testing.T) {
+	t.Log("Expecting CreateBundle and VerifyBundle to package the repository in a file.")
+	dir, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	runGit(t, "tag", "v1")
+	commitFile(t, "file", "2", "second")
+	bundle := dir + ".bundle"
+	defer os.Remove(bundle)
+
+	// Run the function
+	t.Log("Running CreateBundle(bundle, \"HEAD\")...")
+	err := CreateBundle(bundle, "HEAD")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if err = VerifyBundle(bundle); err != nil {
+		t.Errorf("Expected the bundle to be valid. Got %s.", err)
+	}
+	if v, expected := runGit(t, "bundle", "list-heads", bundle), runGit(t, "rev-parse", "HEAD")+" HEAD"; v != expected {
+		t.Errorf("Expected the bundle to contain '%s'. Got '%s'.", expected, v)
+	}
+
+	// Run the function
+	t.Log("Running CreateBundleSince(bundle, \"v1\")...")
+	err = CreateBundleSince(bundle+".inc", "v1")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	defer os.Remove(bundle + ".inc")
+	if err = VerifyBundle(bundle + ".inc"); err != nil {
+		t.Errorf("Expected the incremental bundle to be valid. Got %s.", err)
+	}
+	other := dir + "/other"
+	initRemoteRepo(t, other)
+	if err = NewRepo(other).VerifyBundle(bundle + ".inc"); err == nil {
+		t.Errorf("Expected the incremental bundle to require v1. Got no error.")
+	}
+
+	// Run the function
+	t.Log("Running VerifyBundle(\"file\")...")
+	if err = VerifyBundle("file"); err == nil {
+		t.Errorf("Expected an error. Got none.")
+	}
+}
+
+func TestCloneFromBundle(t *testing.T) {
+	t.Log("Expecting CloneFromBundle and FetchFromBundle to import bundles.")
+	dir, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	runGit(t, "tag", "v1")
+	bundle := dir + ".bundle"
+	defer os.Remove(bundle)
+	runGit(t, "bundle", "create", "-q", bundle, "--all")
+	cloneDir := dir + ".clone"
+	defer os.RemoveAll(cloneDir)
+
+	// Run the function
+	t.Log("Running CloneFromBundle(bundle, cloneDir)...")
+	repo, err := CloneFromBundle(bundle, cloneDir)
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v, expected := runGitIn(t, cloneDir, "rev-parse", "HEAD"), runGit(t, "rev-parse", "HEAD"); v != expected {
+		t.Errorf("Expected the clone HEAD to be %s. Got %s.", expected, v)
+	}
+
+	commitFile(t, "file", "2", "second")
+	runGit(t, "tag", "v2")
+	runGit(t, "bundle", "create", "-q", bundle+".inc", "master", "v2", "^v1")
+	defer os.Remove(bundle + ".inc")
+
+	// Run the function
+	t.Log("Running FetchFromBundle(incremental bundle) in the clone...")
+	err = repo.FetchFromBundle(bundle + ".inc")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v, expected := runGitIn(t, cloneDir, "rev-parse", "bundle/master"), runGit(t, "rev-parse", "HEAD"); v != expected {
+		t.Errorf("Expected bundle/master to be %s. Got %s.", expected, v)
+	}
+	if v := runGitIn(t, cloneDir, "tag", "--list", "v2"); v != "v2" {
+		t.Errorf("Expected the tag v2 to be fetched. Got '%s'.", v)
+	}
+
+	// Run the function
+	t.Log("Running CloneFromBundle(\"file\", ...)...")
+	if _, err = CloneFromBundle("file", dir+".invalid"); err == nil {
+		os.RemoveAll(dir + ".invalid")
+		t.Errorf("Expected an error. Got none.")
+	}
+}
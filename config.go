@@ -0,0 +1,285 @@
+package git
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/forj-oss/forjj-modules/trace"
+)
+
+// ConfigGet returns the value of a git config key.
+// found is false if the key is not set.
+func (r *Repo) ConfigGet(key string) (value string, found bool, err error) {
+	v, found, err := r.GetConfig(ScopeDefault, key)
+	return string(v), found, err
+}
+
+// Scope is a git configuration file.
+type Scope int
+
+// Values of Scope
+const (
+	// ScopeDefault reads all configuration files, and writes the repository one.
+	ScopeDefault Scope = iota
+	// ScopeLocal is the repository configuration, .git/config.
+	ScopeLocal
+	// ScopeGlobal is the user configuration, like ~/.gitconfig.
+	ScopeGlobal
+	// ScopeSystem is the configuration of all users, like /etc/gitconfig.
+	ScopeSystem
+)
+
+var scopeNames = []string{"default", "local", "global", "system"}
+
+// String returns the name of the scope, like "global".
+func (s Scope) String() string {
+	if s < 0 || int(s) >= len(scopeNames) {
+		return "unknown"
+	}
+	return scopeNames[s]
+}
+
+// configOpts returns the git config command with the option selecting the scope.
+func (s Scope) configOpts() []string {
+	if s == ScopeDefault {
+		return []string{"config"}
+	}
+	return []string{"config", "--" + s.String()}
+}
+
+// ConfigValue is the value of a git config key, returned by GetConfig.
+type ConfigValue string
+
+// String returns the value as is.
+func (v ConfigValue) String() string {
+	return string(v)
+}
+
+// Bool returns the value as a git boolean: true, yes, on or a non-zero number are true,
+// false, no, off, 0 or an empty value are false.
+// A key set without value, like "bare" in "[core]", is returned as an empty value by GetConfig
+// while git reads it as true. Use GetConfigBool to read booleans from the configuration.
+func (v ConfigValue) Bool() (bool, error) {
+	switch strings.ToLower(string(v)) {
+	case "true", "yes", "on":
+		return true, nil
+	case "false", "no", "off", "":
+		return false, nil
+	}
+	n, err := v.Int()
+	if err != nil {
+		return false, fmt.Errorf("Invalid boolean '%s'", v)
+	}
+	return n != 0, nil
+}
+
+// Int returns the value as a git integer, with an optional unit k, m or g, like "10m".
+func (v ConfigValue) Int() (int64, error) {
+	text, unit := strings.ToLower(string(v)), int64(1)
+	if text != "" {
+		switch text[len(text)-1] {
+		case 'k':
+			unit = 1 << 10
+		case 'm':
+			unit = 1 << 20
+		case 'g':
+			unit = 1 << 30
+		}
+		if unit > 1 {
+			text = text[:len(text)-1]
+		}
+	}
+	n, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid integer '%s'", v)
+	}
+	return n * unit, nil
+}
+
+// GetConfig returns the value of a git config key in scope, the last one if the key is set several times.
+// found is false if the key is not set, or is not a valid key. An error is returned only if git fails
+// to read the configuration, like an invalid configuration file.
+func (r *Repo) GetConfig(scope Scope, key string) (value ConfigValue, found bool, err error) {
+	v, err := r.Get(append(scope.configOpts(), "--get", key)...)
+	// git config exits with 1 if the key is not set.
+	if exitCode(err) == 1 {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("Unable to read the config '%s'. %s", key, err)
+	}
+	return ConfigValue(v), true, nil
+}
+
+// GetConfigBool returns the value of a git config key in scope as a boolean, as git reads it.
+// A key set without value is true. found is false if the key is not set. An error is returned
+// if the value is not a boolean.
+func (r *Repo) GetConfigBool(scope Scope, key string) (value, found bool, err error) {
+	v, err := r.Get(append(scope.configOpts(), "--type=bool", "--get", key)...)
+	// git config exits with 1 if the key is not set.
+	if exitCode(err) == 1 {
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, fmt.Errorf("Unable to read the boolean config '%s'. %s", key, err)
+	}
+	return v == "true", true, nil
+}
+
+// SetConfig sets a git config key in scope. ScopeDefault writes the repository configuration.
+func (r *Repo) SetConfig(scope Scope, key, value string) error {
+	if _, err := r.Get(append(scope.configOpts(), key, value)...); err != nil {
+		return fmt.Errorf("Unable to set the %s config '%s'. %s", scope, key, err)
+	}
+	return nil
+}
+
+// UnsetConfig removes a git config key from scope. ScopeDefault writes the repository configuration.
+// Nothing is done if the key is not set.
+func (r *Repo) UnsetConfig(scope Scope, key string) error {
+	_, err := r.Get(append(scope.configOpts(), "--unset-all", key)...)
+	// git config exits with 5 if the key is not set.
+	if err != nil && exitCode(err) != 5 {
+		return fmt.Errorf("Unable to unset the %s config '%s'. %s", scope, key, err)
+	}
+	return nil
+}
+
+// EnsureConfig sets in scope the keys of values which are not set in any configuration file,
+// like user.name before a commit. Keys already set keep their value.
+// It returns the keys set, sorted.
+func (r *Repo) EnsureConfig(scope Scope, values map[string]string) ([]string, error) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	set := make([]string, 0)
+	for _, key := range keys {
+		if _, found, err := r.GetConfig(ScopeDefault, key); err != nil {
+			return set, err
+		} else if found {
+			continue
+		}
+		if err := r.SetConfig(scope, key, values[key]); err != nil {
+			return set, err
+		}
+		set = append(set, key)
+	}
+	return set, nil
+}
+
+// ConfigGetDefault returns the value of a git config key, or fallback if not set.
+// If git fails to read the config, a warning is logged and fallback is returned.
+// Use ConfigGet to get the error.
+func (r *Repo) ConfigGetDefault(key, fallback string) string {
+	value, found, err := r.ConfigGet(key)
+	if err != nil {
+		gotrace.Warning("%s. Using '%s'.", err, fallback)
+		return fallback
+	}
+	if !found {
+		return fallback
+	}
+	return value
+}
+
+// BranchConfigGet returns the value of the config key branch.<branch>.<key>.
+// found is false if the key is not set.
+func (r *Repo) BranchConfigGet(branch, key string) (value string, found bool, err error) {
+	if !r.localBranchExists(branch) {
+		return "", false, fmt.Errorf("Unable to read the config of branch '%s'. The branch does not exist", branch)
+	}
+	return r.ConfigGet("branch." + branch + "." + key)
+}
+
+// BranchConfigSet sets the config key branch.<branch>.<key> in the repository configuration.
+func (r *Repo) BranchConfigSet(branch, key, value string) error {
+	if !r.localBranchExists(branch) {
+		return fmt.Errorf("Unable to set the config of branch '%s'. The branch does not exist", branch)
+	}
+	return r.SetConfig(ScopeLocal, "branch."+branch+"."+key, value)
+}
+
+// DefaultInitBranch returns the branch name used when a repository is created.
+// It reads init.defaultBranch and falls back to "master" if not set.
+func (r *Repo) DefaultInitBranch() (string, error) {
+	v, found, err := r.ConfigGet("init.defaultBranch")
+	if err != nil {
+		return "", err
+	}
+	if !found || v == "" {
+		return "master", nil
+	}
+	return v, nil
+}
+
+// PullMode is the way git pull integrates the upstream changes, returned by PullStrategy.
+type PullMode int
+
+// Values of PullMode
+const (
+	// PullMerge fast-forwards when possible and creates a merge commit otherwise.
+	PullMerge PullMode = iota
+	// PullMergeNoFF always creates a merge commit.
+	PullMergeNoFF
+	// PullFastForwardOnly fails if the branch cannot be fast-forwarded.
+	PullFastForwardOnly
+	// PullRebase rebases the local commits on the upstream.
+	PullRebase
+)
+
+var pullModeNames = []string{"merge", "merge --no-ff", "fast-forward only", "rebase"}
+
+// String returns the name of the mode, like "rebase".
+func (m PullMode) String() string {
+	if m < 0 || int(m) >= len(pullModeNames) {
+		return "unknown"
+	}
+	return pullModeNames[m]
+}
+
+// PullStrategy returns how git pull integrates the upstream of the current branch, as configured
+// by branch.<branch>.rebase, pull.rebase and pull.ff. pull.ff=only takes precedence over a rebase.
+func (r *Repo) PullStrategy() (PullMode, error) {
+	ff, _, err := r.ConfigGet("pull.ff")
+	if err != nil {
+		return PullMerge, err
+	}
+	if strings.ToLower(ff) == "only" {
+		return PullFastForwardOnly, nil
+	}
+
+	var rebase string
+	found := false
+	if branch, _ := r.Get("symbolic-ref", "--short", "-q", "HEAD"); branch != "" {
+		rebase, found, err = r.ConfigGet("branch." + branch + ".rebase")
+	}
+	if err == nil && !found {
+		rebase, _, err = r.ConfigGet("pull.rebase")
+	}
+	if err != nil {
+		return PullMerge, err
+	}
+	// pull.rebase is a boolean, or merges or interactive to rebase too.
+	if rebase != "" && !isFalse(rebase) {
+		return PullRebase, nil
+	}
+
+	if ff != "" && isFalse(ff) {
+		return PullMergeNoFF, nil
+	}
+	return PullMerge, nil
+}
+
+// isFalse returns true if value is a git boolean false value.
+func isFalse(value string) bool {
+	switch strings.ToLower(value) {
+	case "false", "no", "off", "0":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,302 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDefaultInitBranch(t *testing.T) {
+	t.Log("Expecting DefaultInitBranch to read init.defaultBranch.")
+	dir, done := initTestRepo(t)
+	defer done()
+
+	runGit(t, "config", "init.defaultBranch", "main")
+
+	// Run the function
+	t.Log("Running DefaultInitBranch()...")
+	branch, err := DefaultInitBranch()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if branch != "main" {
+		t.Errorf("Expected 'main'. Got '%s'.", branch)
+	}
+
+	// Run the function
+	t.Log("Running EnsureRepoExist(\"new\")...")
+	if _, err = EnsureRepoExist(dir + "/new"); err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+
+	// Test the result
+	out, _ := exec.Command("git", "-C", dir+"/new", "symbolic-ref", "HEAD").Output()
+	if v := strings.Trim(string(out), " \n"); v != "refs/heads/main" {
+		t.Errorf("Expected the new repo HEAD to be 'refs/heads/main'. Got '%s'.", v)
+	}
+}
+
+func TestConfigGetDefault(t *testing.T) {
+	t.Log("Expecting ConfigGetDefault to return the value or the fallback.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	runGit(t, "config", "deploy.target", "production")
+
+	// Run the function
+	t.Log("Running ConfigGetDefault(\"deploy.target\", \"staging\")...")
+	if v := ConfigGetDefault("deploy.target", "staging"); v != "production" {
+		t.Errorf("Expected 'production'. Got '%s'.", v)
+	}
+
+	// Run the function
+	t.Log("Running ConfigGetDefault(\"deploy.unset\", \"staging\")...")
+	if v := ConfigGetDefault("deploy.unset", "staging"); v != "staging" {
+		t.Errorf("Expected 'staging'. Got '%s'.", v)
+	}
+
+	// Run the function
+	t.Log("Running ConfigGet(\"deploy.unset\")...")
+	if v, found, err := ConfigGet("deploy.unset"); err != nil {
+		t.Errorf("Expected no error. Got %s.", err)
+	} else if found || v != "" {
+		t.Errorf("Expected the key not to be found. Got '%s'.", v)
+	}
+}
+
+func TestBranchConfig(t *testing.T) {
+	t.Log("Expecting BranchConfigSet and BranchConfigGet to round-trip a branch config key.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	runGit(t, "branch", "release/1.0")
+
+	// Run the function
+	t.Log("Running BranchConfigSet(\"release/1.0\", \"deployTarget\", \"production\")...")
+	if err := BranchConfigSet("release/1.0", "deployTarget", "production"); err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+
+	// Run the function
+	t.Log("Running BranchConfigGet(\"release/1.0\", \"deployTarget\")...")
+	value, found, err := BranchConfigGet("release/1.0", "deployTarget")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if !found || value != "production" {
+		t.Errorf("Expected 'production'. Got '%s' (found: %t).", value, found)
+	}
+	if v := runGit(t, "config", "branch.release/1.0.deploytarget"); v != "production" {
+		t.Errorf("Expected the git config to be 'production'. Got '%s'.", v)
+	}
+
+	// Run the function
+	t.Log("Running BranchConfigSet(\"unknown\", ...)...")
+	if err = BranchConfigSet("unknown", "deployTarget", "production"); err == nil {
+		t.Errorf("Expected an error on an unknown branch. Got none.")
+	}
+	if _, _, err = BranchConfigGet("unknown", "deployTarget"); err == nil {
+		t.Errorf("Expected an error on an unknown branch. Got none.")
+	}
+}
+
+func TestPullStrategy(t *testing.T) {
+	t.Log("Expecting PullStrategy to report the pull mode configured.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	tests := []struct {
+		config   []string
+		expected PullMode
+	}{
+		{nil, PullMerge},
+		{[]string{"pull.ff", "false"}, PullMergeNoFF},
+		{[]string{"pull.rebase", "true"}, PullRebase},
+		{[]string{"pull.rebase", "merges"}, PullRebase},
+		{[]string{"branch.master.rebase", "false"}, PullMergeNoFF},
+		{[]string{"pull.ff", "only"}, PullFastForwardOnly},
+	}
+	for _, test := range tests {
+		if test.config != nil {
+			runGit(t, "config", test.config[0], test.config[1])
+		}
+
+		// Run the function
+		mode, err := PullStrategy()
+
+		// Test the result
+		if err != nil {
+			t.Errorf("Expected no error. Got %s.", err)
+		} else if mode != test.expected {
+			t.Errorf("Expected %v to set the mode '%s'. Got '%s'.", test.config, test.expected, mode)
+		}
+	}
+}
+
+func TestConfigScopes(t *testing.T) {
+	t.Log("Expecting GetConfig, SetConfig, UnsetConfig and EnsureConfig to use the scope given.")
+	dir, done := initTestRepo(t)
+	defer done()
+
+	t.Setenv("GIT_CONFIG_GLOBAL", dir+"/global.config")
+
+	// Run the function
+	t.Log("Running SetConfig() in the local and global scopes...")
+	localErr := SetConfig(ScopeLocal, "deploy.target", "production")
+	globalErr := SetConfig(ScopeGlobal, "deploy.region", "eu")
+
+	// Test the result
+	if localErr != nil || globalErr != nil {
+		t.Fatalf("Expected no error. Got %v and %v.", localErr, globalErr)
+	}
+	tests := []struct {
+		scope    Scope
+		key      string
+		expected ConfigValue
+		found    bool
+	}{
+		{ScopeLocal, "deploy.target", "production", true},
+		{ScopeDefault, "deploy.target", "production", true},
+		{ScopeGlobal, "deploy.target", "", false},
+		{ScopeLocal, "deploy.region", "", false},
+		{ScopeDefault, "deploy.region", "eu", true},
+	}
+	for _, test := range tests {
+		// Run the function
+		v, found, err := GetConfig(test.scope, test.key)
+
+		// Test the result
+		if err != nil {
+			t.Errorf("Expected no error for %s '%s'. Got %s.", test.scope, test.key, err)
+		} else if v != test.expected || found != test.found {
+			t.Errorf("Expected '%s' found %t for %s '%s'. Got '%s' %t.", test.expected, test.found, test.scope, test.key, v, found)
+		}
+	}
+
+	// Run the function
+	t.Log("Running UnsetConfig(ScopeLocal, \"deploy.target\") twice...")
+	if err := UnsetConfig(ScopeLocal, "deploy.target"); err != nil {
+		t.Errorf("Expected no error. Got %s.", err)
+	}
+	if err := UnsetConfig(ScopeLocal, "deploy.target"); err != nil {
+		t.Errorf("Expected no error for an unset key. Got %s.", err)
+	}
+
+	// Test the result
+	if _, found, _ := GetConfig(ScopeDefault, "deploy.target"); found {
+		t.Errorf("Expected deploy.target to be unset.")
+	}
+
+	// Run the function
+	t.Log("Running EnsureConfig(ScopeLocal, ...)...")
+	set, err := EnsureConfig(ScopeLocal, map[string]string{"user.name": "Bot", "deploy.region": "us", "deploy.target": "staging"})
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if !reflect.DeepEqual(set, []string{"deploy.target"}) {
+		t.Errorf("Expected only deploy.target to be set. Got %q.", set)
+	}
+	if v := runGit(t, "config", "--local", "user.name"); v != "Test User" {
+		t.Errorf("Expected user.name to be kept. Got '%s'.", v)
+	}
+	if v := runGit(t, "config", "--local", "deploy.target"); v != "staging" {
+		t.Errorf("Expected deploy.target to be 'staging'. Got '%s'.", v)
+	}
+
+	writeFile(t, dir+"/global.config", "[broken")
+
+	// Run the function
+	t.Log("Running GetConfig() with an invalid configuration file...")
+	_, found, err := GetConfig(ScopeGlobal, "deploy.region")
+
+	// Test the result
+	if err == nil || found {
+		t.Errorf("Expected an error. Got found %t and %v.", found, err)
+	}
+}
+
+func TestConfigValue(t *testing.T) {
+	t.Log("Expecting ConfigValue to convert git booleans and integers.")
+	bools := map[ConfigValue]bool{"true": true, "Yes": true, "on": true, "1": true, "2k": true,
+		"false": false, "NO": false, "off": false, "0": false, "": false}
+	for value, expected := range bools {
+		// Run the function
+		v, err := value.Bool()
+
+		// Test the result
+		if err != nil || v != expected {
+			t.Errorf("Expected '%s' to be %t. Got %t, %v.", value, expected, v, err)
+		}
+	}
+	ints := map[ConfigValue]int64{"42": 42, "-3": -3, "2k": 2048, "1M": 1 << 20, "1g": 1 << 30}
+	for value, expected := range ints {
+		// Run the function
+		v, err := value.Int()
+
+		// Test the result
+		if err != nil || v != expected {
+			t.Errorf("Expected '%s' to be %d. Got %d, %v.", value, expected, v, err)
+		}
+	}
+	for _, value := range []ConfigValue{"maybe", "k", ""} {
+		if _, err := value.Int(); err == nil {
+			t.Errorf("Expected '%s' not to be an integer.", value)
+		}
+	}
+	if _, err := ConfigValue("maybe").Bool(); err == nil {
+		t.Errorf("Expected 'maybe' not to be a boolean.")
+	}
+}
+
+func TestGetConfigBool(t *testing.T) {
+	t.Log("Expecting GetConfigBool to read booleans as git does.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	config, err := os.OpenFile(".git/config", os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("Unable to open the config file. %s", err)
+	}
+	config.WriteString("[test]\n\tnovalue\n\tempty =\n\tyes = on\n\tinvalid = maybe\n")
+	config.Close()
+
+	tests := []struct {
+		key      string
+		expected bool
+		found    bool
+	}{
+		{"test.novalue", true, true},
+		{"test.empty", false, true},
+		{"test.yes", true, true},
+		{"test.missing", false, false},
+	}
+	for _, test := range tests {
+		// Run the function
+		v, found, err := GetConfigBool(ScopeLocal, test.key)
+
+		// Test the result
+		if err != nil {
+			t.Errorf("Expected no error for '%s'. Got %s.", test.key, err)
+		} else if v != test.expected || found != test.found {
+			t.Errorf("Expected '%s' to be %t, found %t. Got %t, %t.", test.key, test.expected, test.found, v, found)
+		}
+	}
+
+	// Run the function
+	_, _, err = GetConfigBool(ScopeLocal, "test.invalid")
+
+	// Test the result
+	if err == nil {
+		t.Errorf("Expected an error for 'test.invalid'.")
+	}
+}
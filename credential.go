@@ -0,0 +1,117 @@
+package git
+
+import (
+	"os/exec"
+	"strings"
+)
+
+const credentialHelperOpt = "credential.helper="
+
+// tokenHelper is the credential helper used for AuthConfig.Token. The credentials are read from
+// the environment of git, so they never appear on a command line.
+const tokenHelper = `!f() { test "$1" = get && echo "username=$GIT_AUTH_USERNAME" && echo "password=$GIT_AUTH_TOKEN"; }; f`
+
+// AuthConfig defines the credentials given to the git commands. See Repo.SetAuth.
+type AuthConfig struct {
+	// SSHKeyPath is the private key used by ssh, instead of the keys of the ssh configuration.
+	SSHKeyPath string
+	// SSHAgentSocket is the socket of the ssh agent to use, instead of SSH_AUTH_SOCK.
+	SSHAgentSocket string
+	// Username and Token are given to https remotes. Username defaults to "git".
+	// The token replaces the credential helpers, including the one set by SetCredentialHelper.
+	Username string
+	Token    string
+	// NoPrompt makes git fail instead of asking for a password or a passphrase,
+	// required when there is no terminal.
+	NoPrompt bool
+}
+
+// SetCredentialHelper defines the credential helper used by commands contacting a remote, like Push.
+// It replaces any helper configured in git, for example:
+//
+//	!f() { echo "username=bot"; echo "password=$TOKEN"; }; f
+//
+// The helper is never logged. An empty helper restores the git configuration.
+func (r *Repo) SetCredentialHelper(helper string) {
+	r.credentialHelper = helper
+}
+
+// SetAuth defines the credentials given to every git command of the repository, through
+// their environment. A nil auth restores the git and ssh configuration.
+// The token requires git 2.31 or later.
+func (r *Repo) SetAuth(auth *AuthConfig) {
+	if auth != nil {
+		copied := *auth
+		auth = &copied
+	}
+	r.auth = auth
+}
+
+// withCredentials adds the credential helper options, if any, before the git command.
+func (r *Repo) withCredentials(opts ...string) []string {
+	if r.credentialHelper == "" || (r.auth != nil && r.auth.Token != "") {
+		return opts
+	}
+	// An empty helper resets the list of helpers configured.
+	return append([]string{"-c", credentialHelperOpt, "-c", credentialHelperOpt + r.credentialHelper}, opts...)
+}
+
+// authEnv returns the environment variables, as "KEY=value", which give the credentials of auth to git.
+func (auth *AuthConfig) authEnv() []string {
+	if auth == nil {
+		return nil
+	}
+
+	env := make([]string, 0)
+	if auth.NoPrompt {
+		env = append(env, "GIT_TERMINAL_PROMPT=0")
+	}
+	if auth.SSHAgentSocket != "" {
+		env = append(env, "SSH_AUTH_SOCK="+auth.SSHAgentSocket)
+	}
+	if auth.SSHKeyPath != "" || auth.NoPrompt {
+		ssh := "ssh"
+		if auth.SSHKeyPath != "" {
+			ssh += " -i " + shellQuote(auth.SSHKeyPath) + " -o IdentitiesOnly=yes"
+		}
+		if auth.NoPrompt {
+			ssh += " -o BatchMode=yes"
+		}
+		env = append(env, "GIT_SSH_COMMAND="+ssh)
+	}
+	if auth.Token != "" {
+		username := auth.Username
+		if username == "" {
+			username = "git"
+		}
+		// An empty helper resets the list of helpers configured.
+		env = append(env, "GIT_CONFIG_COUNT=2",
+			"GIT_CONFIG_KEY_0=credential.helper", "GIT_CONFIG_VALUE_0=",
+			"GIT_CONFIG_KEY_1=credential.helper", "GIT_CONFIG_VALUE_1="+tokenHelper,
+			"GIT_AUTH_USERNAME="+username, "GIT_AUTH_TOKEN="+auth.Token)
+	}
+	return env
+}
+
+// addEnv adds the environment variables env, as "KEY=value", to the environment of cmd.
+func addEnv(cmd *exec.Cmd, env []string) {
+	if len(env) == 0 {
+		return
+	}
+	if cmd.Env == nil {
+		cmd.Env = cmd.Environ()
+	}
+	cmd.Env = append(cmd.Env, env...)
+}
+
+// redactCredentials returns a copy of the git command options without credential values.
+func redactCredentials(opts []string) []string {
+	redacted := make([]string, len(opts))
+	for i, opt := range opts {
+		if strings.HasPrefix(opt, credentialHelperOpt) && opt != credentialHelperOpt {
+			opt = credentialHelperOpt + "<redacted>"
+		}
+		redacted[i] = opt
+	}
+	return redacted
+}
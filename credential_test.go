@@ -0,0 +1,134 @@
+package git
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSetCredentialHelper(t *testing.T) {
+	t.Log("Expecting SetCredentialHelper to provide credentials to git without logging them.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	SetCredentialHelper(`!f() { echo "username=bot"; echo "password=s3cr3t"; }; f`)
+	defer SetCredentialHelper("")
+
+	// Run the function
+	t.Log("Running git credential fill with the credential helper...")
+	v, err := defaultRepo.getWithInput("protocol=https\nhost=example.com\n\n", defaultRepo.withCredentials("credential", "fill")...)
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if !strings.Contains(v, "password=s3cr3t") {
+		t.Errorf("Expected the helper to provide the password. Got '%s'.", v)
+	}
+
+	// Run the function
+	t.Log("Running Do() with the credential helper...")
+	var logged string
+	SetLogFunc(func(text string) { logged += text })
+	Do(defaultRepo.withCredentials("version")...)
+
+	// Test the result
+	if strings.Contains(logged, "s3cr3t") {
+		t.Errorf("Expected the helper to be masked. Got '%s'.", logged)
+	}
+	if !strings.Contains(logged, "credential.helper=<redacted>") {
+		t.Errorf("Expected the helper to be displayed as redacted. Got '%s'.", logged)
+	}
+}
+
+func TestSetAuth(t *testing.T) {
+	t.Log("Expecting SetAuth to give the credentials to git through its environment.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	SetCredentialHelper(`!f() { echo "username=bot"; echo "password=helper"; }; f`)
+	defer SetCredentialHelper("")
+	auth := &AuthConfig{SSHKeyPath: "/keys/id rsa", SSHAgentSocket: "/tmp/agent.sock", Token: "t0ken", NoPrompt: true}
+	SetAuth(auth)
+	defer SetAuth(nil)
+	// Changes after SetAuth are ignored.
+	auth.Token = "changed"
+
+	// Run the function
+	t.Log("Running git credential fill with a token...")
+	v, err := defaultRepo.getWithInput("protocol=https\nhost=example.com\n\n", defaultRepo.withCredentials("credential", "fill")...)
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if !strings.Contains(v, "username=git\n") || !strings.HasSuffix(v, "password=t0ken") {
+		t.Errorf("Expected the token to replace the credential helper. Got '%s'.", v)
+	}
+
+	// Run the function
+	t.Log("Running a git alias displaying its environment...")
+	v, err = Get("-c", "alias.showenv=!env", "showenv")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	for _, expected := range []string{
+		"GIT_TERMINAL_PROMPT=0",
+		"SSH_AUTH_SOCK=/tmp/agent.sock",
+		"GIT_SSH_COMMAND=ssh -i '/keys/id rsa' -o IdentitiesOnly=yes -o BatchMode=yes",
+	} {
+		if !strings.Contains(v, expected+"\n") {
+			t.Errorf("Expected '%s' in the environment. Got '%s'.", expected, v)
+		}
+	}
+
+	SetAuth(&AuthConfig{NoPrompt: true})
+
+	// Run the function
+	t.Log("Running git credential fill without credentials and without prompt...")
+	_, err = defaultRepo.getWithInput("protocol=https\nhost=example.com\n\n", "-c", "credential.helper=", "credential", "fill")
+
+	// Test the result
+	if err == nil {
+		t.Errorf("Expected an error instead of a prompt. Got none.")
+	}
+}
+
+func TestRemoteQueriesCredentials(t *testing.T) {
+	t.Log("Expecting the remote queries to use the credential helper.")
+	dir, done := initTestRepo(t)
+	defer done()
+
+	remoteDir := dir + "/remote"
+	initRemoteRepo(t, remoteDir)
+	runGit(t, "remote", "add", "origin", remoteDir)
+	repo := NewRepo(dir)
+	repo.SetCredentialHelper("store")
+	runner := &RecordingRunner{Next: ExecRunner{}}
+	repo.SetRunner(runner)
+
+	// Run the function
+	t.Log("Running RemoteReachable(), RemoteHasNewCommits(), RefIsNew() and PushCreatesBranch()...")
+	repo.RemoteReachable(remoteDir, 10*time.Second)
+	repo.RemoteHasNewCommits("origin", "master")
+	repo.RefIsNew("origin", "master")
+	repo.PushCreatesBranch("origin", "master")
+
+	// Test the result
+	count := 0
+	for _, command := range runner.Commands() {
+		args := strings.Join(command.Args, " ")
+		if !strings.Contains(args, " ls-remote ") {
+			continue
+		}
+		count++
+		if !strings.Contains(args, "credential.helper=<redacted>") {
+			t.Errorf("Expected '%s' to use the credential helper.", args)
+		}
+	}
+	if count != 4 {
+		t.Errorf("Expected 4 ls-remote commands. Got %d.", count)
+	}
+}
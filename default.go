@@ -0,0 +1,734 @@
+package git
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Package functions run git in the current directory, using the default repository.
+
+// Do calls Repo.Do on the repository of the current directory.
+func Do(opts ...string) int {
+	return defaultRepo.Do(opts...)
+}
+
+// DoContext calls Repo.DoContext on the repository of the current directory.
+func DoContext(ctx context.Context, opts ...string) (int, error) {
+	return defaultRepo.DoContext(ctx, opts...)
+}
+
+// GetStatus calls Repo.GetStatus on the repository of the current directory.
+func GetStatus() *Status {
+	return defaultRepo.GetStatus()
+}
+
+// StatusShort calls Repo.StatusShort on the repository of the current directory.
+func StatusShort() ([]string, error) {
+	return defaultRepo.StatusShort()
+}
+
+// Get calls Repo.Get on the repository of the current directory.
+func Get(opts ...string) (string, error) {
+	return defaultRepo.Get(opts...)
+}
+
+// GetContext calls Repo.GetContext on the repository of the current directory.
+func GetContext(ctx context.Context, opts ...string) (string, error) {
+	return defaultRepo.GetContext(ctx, opts...)
+}
+
+// GetWithStatusCode calls Repo.GetWithStatusCode on the repository of the current directory.
+func GetWithStatusCode(opts ...string) (string, int) {
+	return defaultRepo.GetWithStatusCode(opts...)
+}
+
+// Commit calls Repo.Commit on the repository of the current directory.
+func Commit(msg string, errorIfEmpty bool) error {
+	return defaultRepo.Commit(msg, errorIfEmpty)
+}
+
+// Push calls Repo.Push on the repository of the current directory.
+func Push() error {
+	return defaultRepo.Push()
+}
+
+// Add calls Repo.Add on the repository of the current directory.
+func Add(files []string) int {
+	return defaultRepo.Add(files)
+}
+
+// AddPathspec calls Repo.AddPathspec on the repository of the current directory.
+func AddPathspec(include []string, exclude []string) error {
+	return defaultRepo.AddPathspec(include, exclude)
+}
+
+// AddPath calls Repo.AddPath on the repository of the current directory.
+func AddPath(dir string) int {
+	return defaultRepo.AddPath(dir)
+}
+
+// RenormalizeLineEndings calls Repo.RenormalizeLineEndings on the repository of the current directory.
+func RenormalizeLineEndings() error {
+	return defaultRepo.RenormalizeLineEndings()
+}
+
+// Branches calls Repo.Branches on the repository of the current directory.
+func Branches() ([]string, error) {
+	return defaultRepo.Branches()
+}
+
+// RemoteBranches calls Repo.RemoteBranches on the repository of the current directory.
+func RemoteBranches() ([]string, error) {
+	return defaultRepo.RemoteBranches()
+}
+
+// RemoteBranchExist calls Repo.RemoteBranchExist on the repository of the current directory.
+func RemoteBranchExist(remote string) (bool, error) {
+	return defaultRepo.RemoteBranchExist(remote)
+}
+
+// BranchExist calls Repo.BranchExist on the repository of the current directory.
+func BranchExist(remote string) (bool, error) {
+	return defaultRepo.BranchExist(remote)
+}
+
+// RemoteStatus calls Repo.RemoteStatus on the repository of the current directory.
+func RemoteStatus(remote string) (string, error) {
+	return defaultRepo.RemoteStatus(remote)
+}
+
+// RemoteExist calls Repo.RemoteExist on the repository of the current directory.
+func RemoteExist(remote string) bool {
+	return defaultRepo.RemoteExist(remote)
+}
+
+// RemoteURL calls Repo.RemoteURL on the repository of the current directory.
+func RemoteURL(remote string) (string, bool, error) {
+	return defaultRepo.RemoteURL(remote)
+}
+
+// RemotePushURL calls Repo.RemotePushURL on the repository of the current directory.
+func RemotePushURL(remote string) (string, bool, error) {
+	return defaultRepo.RemotePushURL(remote)
+}
+
+// EnsureRemoteIs calls Repo.EnsureRemoteIs on the repository of the current directory.
+func EnsureRemoteIs(name, url string) error {
+	return defaultRepo.EnsureRemoteIs(name, url)
+}
+
+// GetCurrentBranch calls Repo.GetCurrentBranch on the repository of the current directory.
+func GetCurrentBranch() string {
+	return defaultRepo.GetCurrentBranch()
+}
+
+// IsBare calls Repo.IsBare on the repository of the current directory.
+func IsBare() (bool, error) {
+	return defaultRepo.IsBare()
+}
+
+// GetStatusCached calls Repo.GetStatusCached on the repository of the current directory.
+func GetStatusCached() *Status {
+	return defaultRepo.GetStatusCached()
+}
+
+// InvalidateStatusCache calls Repo.InvalidateStatusCache on the repository of the current directory.
+func InvalidateStatusCache() {
+	defaultRepo.InvalidateStatusCache()
+}
+
+// SetDefaultRemote sets the DefaultRemote of the repository of the current directory.
+func SetDefaultRemote(remote string) {
+	defaultRepo.DefaultRemote = remote
+}
+
+// SetCredentialHelper calls Repo.SetCredentialHelper on the repository of the current directory.
+func SetCredentialHelper(helper string) {
+	defaultRepo.SetCredentialHelper(helper)
+}
+
+// SetAuth calls Repo.SetAuth on the repository of the current directory.
+func SetAuth(auth *AuthConfig) {
+	defaultRepo.SetAuth(auth)
+}
+
+// SetRunner calls Repo.SetRunner on the repository of the current directory.
+func SetRunner(runner Runner) {
+	defaultRepo.SetRunner(runner)
+}
+
+// AllBranchesTracking calls Repo.AllBranchesTracking on the repository of the current directory.
+func AllBranchesTracking() ([]BranchStatus, error) {
+	return defaultRepo.AllBranchesTracking()
+}
+
+// SyncStatus calls Repo.SyncStatus on the repository of the current directory.
+func SyncStatus() (string, error) {
+	return defaultRepo.SyncStatus()
+}
+
+// PrunableBranches calls Repo.PrunableBranches on the repository of the current directory.
+func PrunableBranches(base string) ([]string, error) {
+	return defaultRepo.PrunableBranches(base)
+}
+
+// PruneMergedTrackingBranches calls Repo.PruneMergedTrackingBranches on the repository of the current directory.
+func PruneMergedTrackingBranches(base string) ([]string, error) {
+	return defaultRepo.PruneMergedTrackingBranches(base)
+}
+
+// CleanupTrackingRefs calls Repo.CleanupTrackingRefs on the repository of the current directory.
+func CleanupTrackingRefs(remote string) (int, error) {
+	return defaultRepo.CleanupTrackingRefs(remote)
+}
+
+// CreateBranch calls Repo.CreateBranch on the repository of the current directory.
+func CreateBranch(name, startPoint string) error {
+	return defaultRepo.CreateBranch(name, startPoint)
+}
+
+// Checkout calls Repo.Checkout on the repository of the current directory.
+func Checkout(name string, opts CheckoutOptions) error {
+	return defaultRepo.Checkout(name, opts)
+}
+
+// DeleteBranch calls Repo.DeleteBranch on the repository of the current directory.
+func DeleteBranch(name string, force bool) error {
+	return defaultRepo.DeleteBranch(name, force)
+}
+
+// SwitchBranch calls Repo.SwitchBranch on the repository of the current directory.
+func SwitchBranch(name string, autoStash bool) error {
+	return defaultRepo.SwitchBranch(name, autoStash)
+}
+
+// CheckoutTracking calls Repo.CheckoutTracking on the repository of the current directory.
+func CheckoutTracking(remote, branch string) error {
+	return defaultRepo.CheckoutTracking(remote, branch)
+}
+
+// EnsureBranchTracked calls Repo.EnsureBranchTracked on the repository of the current directory.
+func EnsureBranchTracked(branch, remote string) error {
+	return defaultRepo.EnsureBranchTracked(branch, remote)
+}
+
+// SetUpstreamBulk calls Repo.SetUpstreamBulk on the repository of the current directory.
+func SetUpstreamBulk(prefix, remote string) (int, error) {
+	return defaultRepo.SetUpstreamBulk(prefix, remote)
+}
+
+// UpstreamRewritten calls Repo.UpstreamRewritten on the repository of the current directory.
+func UpstreamRewritten(branch string) (bool, error) {
+	return defaultRepo.UpstreamRewritten(branch)
+}
+
+// HaveDiverged calls Repo.HaveDiverged on the repository of the current directory.
+func HaveDiverged(a, b string) (bool, error) {
+	return defaultRepo.HaveDiverged(a, b)
+}
+
+// BranchesByActivity calls Repo.BranchesByActivity on the repository of the current directory.
+func BranchesByActivity(remote bool) ([]BranchActivity, error) {
+	return defaultRepo.BranchesByActivity(remote)
+}
+
+// ConfigGet calls Repo.ConfigGet on the repository of the current directory.
+func ConfigGet(key string) (value string, found bool, err error) {
+	return defaultRepo.ConfigGet(key)
+}
+
+// GetConfig calls Repo.GetConfig on the repository of the current directory.
+func GetConfig(scope Scope, key string) (ConfigValue, bool, error) {
+	return defaultRepo.GetConfig(scope, key)
+}
+
+// GetConfigBool calls Repo.GetConfigBool on the repository of the current directory.
+func GetConfigBool(scope Scope, key string) (bool, bool, error) {
+	return defaultRepo.GetConfigBool(scope, key)
+}
+
+// SetConfig calls Repo.SetConfig on the repository of the current directory.
+func SetConfig(scope Scope, key, value string) error {
+	return defaultRepo.SetConfig(scope, key, value)
+}
+
+// UnsetConfig calls Repo.UnsetConfig on the repository of the current directory.
+func UnsetConfig(scope Scope, key string) error {
+	return defaultRepo.UnsetConfig(scope, key)
+}
+
+// EnsureConfig calls Repo.EnsureConfig on the repository of the current directory.
+func EnsureConfig(scope Scope, values map[string]string) ([]string, error) {
+	return defaultRepo.EnsureConfig(scope, values)
+}
+
+// ConfigGetDefault calls Repo.ConfigGetDefault on the repository of the current directory.
+func ConfigGetDefault(key, fallback string) string {
+	return defaultRepo.ConfigGetDefault(key, fallback)
+}
+
+// BranchConfigGet calls Repo.BranchConfigGet on the repository of the current directory.
+func BranchConfigGet(branch, key string) (value string, found bool, err error) {
+	return defaultRepo.BranchConfigGet(branch, key)
+}
+
+// BranchConfigSet calls Repo.BranchConfigSet on the repository of the current directory.
+func BranchConfigSet(branch, key, value string) error {
+	return defaultRepo.BranchConfigSet(branch, key, value)
+}
+
+// DefaultInitBranch calls Repo.DefaultInitBranch on the repository of the current directory.
+func DefaultInitBranch() (string, error) {
+	return defaultRepo.DefaultInitBranch()
+}
+
+// TrackedStatus calls Repo.TrackedStatus on the repository of the current directory.
+func TrackedStatus(paths []string) (map[string]bool, error) {
+	return defaultRepo.TrackedStatus(paths)
+}
+
+// PathDirty calls Repo.PathDirty on the repository of the current directory.
+func PathDirty(path string) (bool, error) {
+	return defaultRepo.PathDirty(path)
+}
+
+// GetPathState calls Repo.GetPathState on the repository of the current directory.
+func GetPathState(path string) (PathState, error) {
+	return defaultRepo.GetPathState(path)
+}
+
+// IgnoreSources calls Repo.IgnoreSources on the repository of the current directory.
+func IgnoreSources(path string) ([]IgnoreMatch, error) {
+	return defaultRepo.IgnoreSources(path)
+}
+
+// FileChanged calls Repo.FileChanged on the repository of the current directory.
+func FileChanged(path, from, to string) (bool, error) {
+	return defaultRepo.FileChanged(path, from, to)
+}
+
+// DiffWithContext calls Repo.DiffWithContext on the repository of the current directory.
+func DiffWithContext(from, to string, contextLines int) (string, error) {
+	return defaultRepo.DiffWithContext(from, to, contextLines)
+}
+
+// MergeDiff calls Repo.MergeDiff on the repository of the current directory.
+func MergeDiff(ref string) (string, error) {
+	return defaultRepo.MergeDiff(ref)
+}
+
+// IndexVsTree calls Repo.IndexVsTree on the repository of the current directory.
+func IndexVsTree(tree string) ([]DiffEntry, error) {
+	return defaultRepo.IndexVsTree(tree)
+}
+
+// CommitByHash calls Repo.CommitByHash on the repository of the current directory.
+func CommitByHash(ref string) (CommitInfo, error) {
+	return defaultRepo.CommitByHash(ref)
+}
+
+// CommitJSON calls Repo.CommitJSON on the repository of the current directory.
+func CommitJSON(ref string) ([]byte, error) {
+	return defaultRepo.CommitJSON(ref)
+}
+
+// CommitCount calls Repo.CommitCount on the repository of the current directory.
+func CommitCount(ref string) (int, error) {
+	return defaultRepo.CommitCount(ref)
+}
+
+// PatchID calls Repo.PatchID on the repository of the current directory.
+func PatchID(ref string) (string, error) {
+	return defaultRepo.PatchID(ref)
+}
+
+// RootCommit calls Repo.RootCommit on the repository of the current directory.
+func RootCommit() (string, error) {
+	return defaultRepo.RootCommit()
+}
+
+// TimeSinceLastCommit calls Repo.TimeSinceLastCommit on the repository of the current directory.
+func TimeSinceLastCommit() (time.Duration, error) {
+	return defaultRepo.TimeSinceLastCommit()
+}
+
+// UnpushedCommits calls Repo.UnpushedCommits on the repository of the current directory.
+func UnpushedCommits() ([]CommitInfo, error) {
+	return defaultRepo.UnpushedCommits()
+}
+
+// LogTopo calls Repo.LogTopo on the repository of the current directory.
+func LogTopo(ref string, max int) ([]CommitInfo, error) {
+	return defaultRepo.LogTopo(ref, max)
+}
+
+// RevListRange calls Repo.RevListRange on the repository of the current directory.
+func RevListRange(from, to string, max int) ([]string, error) {
+	return defaultRepo.RevListRange(from, to, max)
+}
+
+// Log calls Repo.Log on the repository of the current directory.
+func Log(opts LogOptions) ([]CommitInfo, error) {
+	return defaultRepo.Log(opts)
+}
+
+// LastCommitOf calls Repo.LastCommitOf on the repository of the current directory.
+func LastCommitOf(path string) (CommitInfo, error) {
+	return defaultRepo.LastCommitOf(path)
+}
+
+// CommitExists calls Repo.CommitExists on the repository of the current directory.
+func CommitExists(sha string) (bool, error) {
+	return defaultRepo.CommitExists(sha)
+}
+
+// FileContributors calls Repo.FileContributors on the repository of the current directory.
+func FileContributors(path string) ([]Contributor, error) {
+	return defaultRepo.FileContributors(path)
+}
+
+// ResolveIdentity calls Repo.ResolveIdentity on the repository of the current directory.
+func ResolveIdentity(name, email string) (string, string, error) {
+	return defaultRepo.ResolveIdentity(name, email)
+}
+
+// CommitStats calls Repo.CommitStats on the repository of the current directory.
+func CommitStats(since, until time.Time) (map[string]int, error) {
+	return defaultRepo.CommitStats(since, until)
+}
+
+// Tags calls Repo.Tags on the repository of the current directory.
+func Tags() ([]Tag, error) {
+	return defaultRepo.Tags()
+}
+
+// DeleteTag calls Repo.DeleteTag on the repository of the current directory.
+func DeleteTag(name string) error {
+	return defaultRepo.DeleteTag(name)
+}
+
+// PushTags calls Repo.PushTags on the repository of the current directory.
+func PushTags(remote string) error {
+	return defaultRepo.PushTags(remote)
+}
+
+// LatestSemverTag calls Repo.LatestSemverTag on the repository of the current directory.
+func LatestSemverTag(prefix string) (string, error) {
+	return defaultRepo.LatestSemverTag(prefix)
+}
+
+// CreateTag calls Repo.CreateTag on the repository of the current directory.
+func CreateTag(name, message string, annotated bool) error {
+	return defaultRepo.CreateTag(name, message, annotated)
+}
+
+// CreateTagWithOptions calls Repo.CreateTagWithOptions on the repository of the current directory.
+func CreateTagWithOptions(name, ref string, opts TagOptions) error {
+	return defaultRepo.CreateTagWithOptions(name, ref, opts)
+}
+
+// TagsSortedByVersion calls Repo.TagsSortedByVersion on the repository of the current directory.
+func TagsSortedByVersion(prefix string) ([]string, error) {
+	return defaultRepo.TagsSortedByVersion(prefix)
+}
+
+// NearestTag calls Repo.NearestTag on the repository of the current directory.
+func NearestTag(ref string) (tag string, distance int, err error) {
+	return defaultRepo.NearestTag(ref)
+}
+
+// TagBehindBranch calls Repo.TagBehindBranch on the repository of the current directory.
+func TagBehindBranch(tag, branch string) (int, error) {
+	return defaultRepo.TagBehindBranch(tag, branch)
+}
+
+// Blame calls Repo.Blame on the repository of the current directory.
+func Blame(path string) ([]BlameLine, error) {
+	return defaultRepo.Blame(path)
+}
+
+// BlameJSON calls Repo.BlameJSON on the repository of the current directory.
+func BlameJSON(path string) ([]byte, error) {
+	return defaultRepo.BlameJSON(path)
+}
+
+// MergeMessagePreview calls Repo.MergeMessagePreview on the repository of the current directory.
+func MergeMessagePreview(ref string) (string, error) {
+	return defaultRepo.MergeMessagePreview(ref)
+}
+
+// MergeTree calls Repo.MergeTree on the repository of the current directory.
+func MergeTree(base, ours, theirs string) (MergeTreeResult, error) {
+	return defaultRepo.MergeTree(base, ours, theirs)
+}
+
+// WouldChangeAnything calls Repo.WouldChangeAnything on the repository of the current directory.
+func WouldChangeAnything(ref string) (bool, error) {
+	return defaultRepo.WouldChangeAnything(ref)
+}
+
+// ApplyCommitChanges calls Repo.ApplyCommitChanges on the repository of the current directory.
+func ApplyCommitChanges(ref string) error {
+	return defaultRepo.ApplyCommitChanges(ref)
+}
+
+// HasUnmergedEntries calls Repo.HasUnmergedEntries on the repository of the current directory.
+func HasUnmergedEntries() (bool, error) {
+	return defaultRepo.HasUnmergedEntries()
+}
+
+// RecordResolution calls Repo.RecordResolution on the repository of the current directory.
+func RecordResolution() error {
+	return defaultRepo.RecordResolution()
+}
+
+// ReplayResolution calls Repo.ReplayResolution on the repository of the current directory.
+func ReplayResolution() ([]string, error) {
+	return defaultRepo.ReplayResolution()
+}
+
+// PackStats calls Repo.PackStats on the repository of the current directory.
+func PackStats() ([]PackInfo, error) {
+	return defaultRepo.PackStats()
+}
+
+// RepairHead calls Repo.RepairHead on the repository of the current directory.
+func RepairHead(defaultBranch string) error {
+	return defaultRepo.RepairHead(defaultBranch)
+}
+
+// GCIfNeeded calls Repo.GCIfNeeded on the repository of the current directory.
+func GCIfNeeded() (bool, error) {
+	return defaultRepo.GCIfNeeded()
+}
+
+// UnreachableCommits calls Repo.UnreachableCommits on the repository of the current directory.
+func UnreachableCommits() ([]string, error) {
+	return defaultRepo.UnreachableCommits()
+}
+
+// Fsck calls Repo.Fsck on the repository of the current directory.
+func Fsck(full bool) ([]FsckIssue, error) {
+	return defaultRepo.Fsck(full)
+}
+
+// ShowFileTo calls Repo.ShowFileTo on the repository of the current directory.
+func ShowFileTo(ref, path string, w io.Writer) error {
+	return defaultRepo.ShowFileTo(ref, path, w)
+}
+
+// ObjectType calls Repo.ObjectType on the repository of the current directory.
+func ObjectType(ref string) (string, error) {
+	return defaultRepo.ObjectType(ref)
+}
+
+// CatFile calls Repo.CatFile on the repository of the current directory.
+func CatFile(sha string) ([]byte, string, error) {
+	return defaultRepo.CatFile(sha)
+}
+
+// ListTree calls Repo.ListTree on the repository of the current directory.
+func ListTree(ref, path string, recursive bool) ([]TreeEntry, error) {
+	return defaultRepo.ListTree(ref, path, recursive)
+}
+
+// UpdateRefs calls Repo.UpdateRefs on the repository of the current directory.
+func UpdateRefs(updates []RefUpdate) error {
+	return defaultRepo.UpdateRefs(updates)
+}
+
+// RefsPointingAt calls Repo.RefsPointingAt on the repository of the current directory.
+func RefsPointingAt(commit string) ([]Ref, error) {
+	return defaultRepo.RefsPointingAt(commit)
+}
+
+// Fetch calls Repo.Fetch on the repository of the current directory.
+func Fetch(remote string, opts FetchOptions) error {
+	return defaultRepo.Fetch(remote, opts)
+}
+
+// Pull calls Repo.Pull on the repository of the current directory.
+func Pull(remote, branch string, opts PullOptions) error {
+	return defaultRepo.Pull(remote, branch, opts)
+}
+
+// RemoteReachable calls Repo.RemoteReachable on the repository of the current directory.
+func RemoteReachable(url string, timeout time.Duration) (bool, error) {
+	return defaultRepo.RemoteReachable(url, timeout)
+}
+
+// RemoteHasNewCommits calls Repo.RemoteHasNewCommits on the repository of the current directory.
+func RemoteHasNewCommits(remote, branch string) (bool, error) {
+	return defaultRepo.RemoteHasNewCommits(remote, branch)
+}
+
+// RefIsNew calls Repo.RefIsNew on the repository of the current directory.
+func RefIsNew(remote, branch string) (bool, error) {
+	return defaultRepo.RefIsNew(remote, branch)
+}
+
+// PushCreatesBranch calls Repo.PushCreatesBranch on the repository of the current directory.
+func PushCreatesBranch(remote, branch string) (bool, error) {
+	return defaultRepo.PushCreatesBranch(remote, branch)
+}
+
+// Remotes calls Repo.Remotes on the repository of the current directory.
+func Remotes() ([]string, error) {
+	return defaultRepo.Remotes()
+}
+
+// PushableRemotes calls Repo.PushableRemotes on the repository of the current directory.
+func PushableRemotes() ([]string, error) {
+	return defaultRepo.PushableRemotes()
+}
+
+// RebaseInfo calls Repo.RebaseInfo on the repository of the current directory.
+func RebaseInfo() (onto, orig, head string, err error) {
+	return defaultRepo.RebaseInfo()
+}
+
+// ResetClean calls Repo.ResetClean on the repository of the current directory.
+func ResetClean(ref string) error {
+	return defaultRepo.ResetClean(ref)
+}
+
+// CheckoutPaths calls Repo.CheckoutPaths on the repository of the current directory.
+func CheckoutPaths(ref string, paths []string) error {
+	return defaultRepo.CheckoutPaths(ref, paths)
+}
+
+// CheckoutWouldConflict calls Repo.CheckoutWouldConflict on the repository of the current directory.
+func CheckoutWouldConflict(ref string) ([]string, error) {
+	return defaultRepo.CheckoutWouldConflict(ref)
+}
+
+// SquashRange calls Repo.SquashRange on the repository of the current directory.
+func SquashRange(from string, message string) error {
+	return defaultRepo.SquashRange(from, message)
+}
+
+// StashFindByMessage calls Repo.StashFindByMessage on the repository of the current directory.
+func StashFindByMessage(substr string) (index int, found bool, err error) {
+	return defaultRepo.StashFindByMessage(substr)
+}
+
+// WorktreeAddDetached calls Repo.WorktreeAddDetached on the repository of the current directory.
+func WorktreeAddDetached(path, commit string) error {
+	return defaultRepo.WorktreeAddDetached(path, commit)
+}
+
+// WorktreeAdd calls Repo.WorktreeAdd on the repository of the current directory.
+func WorktreeAdd(path, commit string) (*Repo, error) {
+	return defaultRepo.WorktreeAdd(path, commit)
+}
+
+// WorktreeList calls Repo.WorktreeList on the repository of the current directory.
+func WorktreeList() ([]Worktree, error) {
+	return defaultRepo.WorktreeList()
+}
+
+// WorktreesStatus calls Repo.WorktreesStatus on the repository of the current directory.
+func WorktreesStatus() ([]WorktreeStatus, error) {
+	return defaultRepo.WorktreesStatus()
+}
+
+// SubmoduleDrift calls Repo.SubmoduleDrift on the repository of the current directory.
+func SubmoduleDrift() ([]DriftedSubmodule, error) {
+	return defaultRepo.SubmoduleDrift()
+}
+
+// Snapshot calls Repo.Snapshot on the repository of the current directory.
+func Snapshot() (Token, error) {
+	return defaultRepo.Snapshot()
+}
+
+// Rollback calls Repo.Rollback on the repository of the current directory.
+func Rollback(token Token) error {
+	return defaultRepo.Rollback(token)
+}
+
+// LFSPatterns calls Repo.LFSPatterns on the repository of the current directory.
+func LFSPatterns() ([]string, error) {
+	return defaultRepo.LFSPatterns()
+}
+
+// IsLFSRepo calls Repo.IsLFSRepo on the repository of the current directory.
+func IsLFSRepo() (bool, error) {
+	return defaultRepo.IsLFSRepo()
+}
+
+// RunSafe calls Repo.RunSafe on the repository of the current directory.
+func RunSafe(subcommand string, args ...string) (string, int, error) {
+	return defaultRepo.RunSafe(subcommand, args...)
+}
+
+// SubmoduleShas calls Repo.SubmoduleShas on the repository of the current directory.
+func SubmoduleShas(ref string) (map[string]string, error) {
+	return defaultRepo.SubmoduleShas(ref)
+}
+
+// VerifyCommitBy calls Repo.VerifyCommitBy on the repository of the current directory.
+func VerifyCommitBy(ref string, allowedKeyIDs []string) (bool, error) {
+	return defaultRepo.VerifyCommitBy(ref, allowedKeyIDs)
+}
+
+// MissingTrackedFiles calls Repo.MissingTrackedFiles on the repository of the current directory.
+func MissingTrackedFiles() ([]string, error) {
+	return defaultRepo.MissingTrackedFiles()
+}
+
+// StagedTextIssues calls Repo.StagedTextIssues on the repository of the current directory.
+func StagedTextIssues() ([]TextIssue, error) {
+	return defaultRepo.StagedTextIssues()
+}
+
+// PreviousHead calls Repo.PreviousHead on the repository of the current directory.
+func PreviousHead() (string, error) {
+	return defaultRepo.PreviousHead()
+}
+
+// RemovePathFromHistory calls Repo.RemovePathFromHistory on the repository of the current directory.
+func RemovePathFromHistory(path string) error {
+	return defaultRepo.RemovePathFromHistory(path)
+}
+
+// Reflog calls Repo.Reflog on the repository of the current directory.
+func Reflog(ref string) ([]ReflogEntry, error) {
+	return defaultRepo.Reflog(ref)
+}
+
+// CreateBundle calls Repo.CreateBundle on the repository of the current directory.
+func CreateBundle(outputPath string, refs ...string) error {
+	return defaultRepo.CreateBundle(outputPath, refs...)
+}
+
+// CreateBundleSince calls Repo.CreateBundleSince on the repository of the current directory.
+func CreateBundleSince(outputPath, since string, refs ...string) error {
+	return defaultRepo.CreateBundleSince(outputPath, since, refs...)
+}
+
+// VerifyBundle calls Repo.VerifyBundle on the repository of the current directory.
+func VerifyBundle(path string) error {
+	return defaultRepo.VerifyBundle(path)
+}
+
+// FetchFromBundle calls Repo.FetchFromBundle on the repository of the current directory.
+func FetchFromBundle(bundlePath string) error {
+	return defaultRepo.FetchFromBundle(bundlePath)
+}
+
+// LargestTrackedFiles calls Repo.LargestTrackedFiles on the repository of the current directory.
+func LargestTrackedFiles(n int) ([]TrackedFile, error) {
+	return defaultRepo.LargestTrackedFiles(n)
+}
+
+// PullStrategy calls Repo.PullStrategy on the repository of the current directory.
+func PullStrategy() (PullMode, error) {
+	return defaultRepo.PullStrategy()
+}
+
+// ResumeState calls Repo.ResumeState on the repository of the current directory.
+func ResumeState() (ResumeInfo, error) {
+	return defaultRepo.ResumeState()
+}
@@ -0,0 +1,104 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FileChanged returns true if path differs between the refs from and to.
+// A path which exists in none of them is reported as unchanged.
+func (r *Repo) FileChanged(path, from, to string) (bool, error) {
+	_, err := r.Get("diff", "--quiet", from, to, "--", path)
+	switch exitCode(err) {
+	case 0:
+		return false, nil
+	case 1:
+		return true, nil
+	}
+	return false, fmt.Errorf("Unable to compare '%s' between '%s' and '%s'. %s", path, from, to, err)
+}
+
+// DiffWithContext returns the diff between the refs from and to, with contextLines lines of context
+// around each change. With 0, hunks only contain the changed lines, so their headers give the exact
+// lines changed. A negative contextLines uses the git default context.
+func (r *Repo) DiffWithContext(from, to string, contextLines int) (string, error) {
+	opts := []string{"diff", "--no-color", "--no-ext-diff"}
+	if contextLines >= 0 {
+		opts = append(opts, "-U"+strconv.Itoa(contextLines))
+	}
+	diff, err := r.getRaw(append(opts, from, to, "--")...)
+	if err != nil {
+		return "", fmt.Errorf("Unable to compare '%s' and '%s'. %s", from, to, err)
+	}
+	return diff, nil
+}
+
+// MergeDiff returns the combined diff of the merge commit ref, as displayed by git show --cc.
+// It only lists the changes which differ from all parents, like conflict resolutions.
+func (r *Repo) MergeDiff(ref string) (string, error) {
+	v, err := r.Get("rev-list", "--parents", "-n", "1", ref, "--")
+	if err != nil {
+		return "", fmt.Errorf("Unable to find the commit '%s'. %s", ref, err)
+	}
+	if len(strings.Fields(v)) < 3 {
+		return "", fmt.Errorf("Unable to get the merge diff. '%s' is not a merge commit", ref)
+	}
+
+	diff, err := r.getRaw("show", "--cc", "--format=", ref, "--")
+	if err != nil {
+		return "", fmt.Errorf("Unable to show the merge diff of '%s'. %s", ref, err)
+	}
+	return strings.TrimLeft(diff, "\n"), nil
+}
+
+// DiffEntry is a file changed between two trees, as reported by git diff raw output.
+type DiffEntry struct {
+	OldMode string
+	NewMode string
+	OldHash string
+	NewHash string
+	// Status is the change: A (added), D (deleted), M (modified), T (type changed), R (renamed) or C (copied).
+	Status string
+	Path   string
+	// OldPath is the source path of a renamed or copied file.
+	OldPath string
+}
+
+// IndexVsTree returns the files which differ between the index and tree, like "HEAD^{tree}" or a commit.
+// The working tree is ignored. A file added in the index is reported with the A status.
+func (r *Repo) IndexVsTree(tree string) ([]DiffEntry, error) {
+	v, err := r.Get("diff-index", "--cached", "-z", tree, "--")
+	if err != nil {
+		return nil, fmt.Errorf("Unable to compare the index with '%s'. %s", tree, err)
+	}
+	return parseRawDiff(v)
+}
+
+// parseRawDiff decodes the git diff raw format with -z:
+// ":<old mode> <new mode> <old hash> <new hash> <status>[<score>]" NUL <path> [NUL <new path>] NUL
+func parseRawDiff(v string) ([]DiffEntry, error) {
+	entries := make([]DiffEntry, 0)
+	fields := strings.Split(v, "\x00")
+	for i := 0; i < len(fields); i++ {
+		if fields[i] == "" {
+			continue
+		}
+		info := strings.Fields(strings.TrimPrefix(fields[i], ":"))
+		if len(info) != 5 || i+1 >= len(fields) {
+			return entries, fmt.Errorf("Unable to parse the diff entry '%s'", fields[i])
+		}
+		entry := DiffEntry{OldMode: info[0], NewMode: info[1], OldHash: info[2], NewHash: info[3], Status: info[4][:1]}
+		i++
+		entry.Path = fields[i]
+		if entry.Status == "R" || entry.Status == "C" {
+			if i+1 >= len(fields) {
+				return entries, fmt.Errorf("Unable to parse the diff entry of '%s'", entry.Path)
+			}
+			i++
+			entry.OldPath, entry.Path = entry.Path, fields[i]
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
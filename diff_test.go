@@ -0,0 +1,172 @@
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFileChanged(t *testing.T) {
+	t.Log("Expecting FileChanged to detect a file change between two refs.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "changed", "1", "first")
+	commitFile(t, "unchanged", "1", "second")
+	runGit(t, "tag", "from")
+	commitFile(t, "changed", "2", "third")
+
+	tests := map[string]bool{
+		"changed":     true,
+		"unchanged":   false,
+		"nonexistent": false,
+	}
+	for aPath, expected := range tests {
+		// Run the function
+		t.Logf("Running FileChanged(%q, \"from\", \"HEAD\")...", aPath)
+		changed, err := FileChanged(aPath, "from", "HEAD")
+
+		// Test the result
+		if err != nil {
+			t.Errorf("Expected no error. Got %s.", err)
+		} else if changed != expected {
+			t.Errorf("Expected '%s' changed to be %t. Got %t.", aPath, expected, changed)
+		}
+	}
+
+	// Run the function
+	t.Log("Running FileChanged(\"changed\", \"unknown\", \"HEAD\")...")
+	if _, err := FileChanged("changed", "unknown", "HEAD"); err == nil {
+		t.Errorf("Expected an error on an unknown ref. Got none.")
+	}
+}
+
+func TestMergeDiff(t *testing.T) {
+	t.Log("Expecting MergeDiff to return the conflict resolution of a merge commit.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "base\n", "first")
+	runGit(t, "checkout", "-q", "-b", "feature")
+	commitFile(t, "file", "feature\n", "feature")
+	commitFile(t, "other", "1\n", "other")
+	runGit(t, "checkout", "-q", "master")
+	commitFile(t, "file", "master\n", "master")
+	if _, code := GetWithStatusCode("merge", "-q", "feature"); code == 0 {
+		t.Fatalf("Expected the merge to conflict.")
+	}
+	commitFile(t, "file", "resolved\n", "merge")
+
+	// Run the function
+	t.Log("Running MergeDiff(\"HEAD\")...")
+	diff, err := MergeDiff("HEAD")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if !strings.HasPrefix(diff, "diff --cc file\n") {
+		t.Errorf("Expected the combined diff of 'file'. Got %q.", diff)
+	}
+	if !strings.Contains(diff, "++resolved\n") {
+		t.Errorf("Expected the resolution to be added. Got %q.", diff)
+	}
+	if strings.Contains(diff, "other") {
+		t.Errorf("Expected the merged file 'other' not to be listed. Got %q.", diff)
+	}
+
+	// Run the function
+	t.Log("Running MergeDiff(\"HEAD^\")...")
+	if _, err = MergeDiff("HEAD^"); err == nil {
+		t.Errorf("Expected an error on a commit which is not a merge. Got none.")
+	}
+}
+
+func TestParseRawDiff(t *testing.T) {
+	t.Log("Expecting parseRawDiff to decode renames.")
+	old := "1111111111111111111111111111111111111111"
+	v := ":100644 100644 " + old + " " + old + " R100\x00old\x00new\x00"
+
+	// Run the function
+	entries, err := parseRawDiff(v)
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	expected := DiffEntry{OldMode: "100644", NewMode: "100644", OldHash: old, NewHash: old, Status: "R", Path: "new", OldPath: "old"}
+	if len(entries) != 1 || entries[0] != expected {
+		t.Errorf("Expected [%+v]. Got %+v.", expected, entries)
+	}
+}
+
+func TestIndexVsTree(t *testing.T) {
+	t.Log("Expecting IndexVsTree to list the files staged compared to a tree.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "modified", "1", "first")
+	commitFile(t, "deleted", "1", "second")
+	commitFile(t, "unstaged", "1", "third")
+	writeFile(t, "modified", "2")
+	writeFile(t, "added", "1")
+	runGit(t, "add", "modified", "added")
+	runGit(t, "rm", "-q", "--cached", "deleted")
+	writeFile(t, "unstaged", "2")
+
+	// Run the function
+	t.Log("Running IndexVsTree(\"HEAD^{tree}\")...")
+	entries, err := IndexVsTree("HEAD^{tree}")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	expected := map[string]string{"added": "A", "deleted": "D", "modified": "M"}
+	if v := len(entries); v != len(expected) {
+		t.Errorf("Expected %d entries. Got %+v.", len(expected), entries)
+	}
+	for _, entry := range entries {
+		if v := expected[entry.Path]; v != entry.Status {
+			t.Errorf("Expected '%s' status to be '%s'. Got '%s'.", entry.Path, v, entry.Status)
+		}
+	}
+	for _, entry := range entries {
+		if entry.Path == "modified" && entry.NewHash != runGit(t, "rev-parse", ":modified") {
+			t.Errorf("Expected the new hash of 'modified' to be the staged blob. Got %s.", entry.NewHash)
+		}
+	}
+}
+
+func TestDiffWithContext(t *testing.T) {
+	t.Log("Expecting DiffWithContext to set the number of context lines of the hunks.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n", "first")
+	commitFile(t, "file", "1\n2\n3\n4\nfive\n6\n7\n8\n9\n10\n", "second")
+
+	tests := map[int]string{
+		0:  "@@ -5 +5 @@",
+		1:  "@@ -4,3 +4,3 @@",
+		-1: "@@ -2,7 +2,7 @@",
+	}
+	for contextLines, expected := range tests {
+		// Run the function
+		diff, err := DiffWithContext("HEAD~1", "HEAD", contextLines)
+
+		// Test the result
+		if err != nil {
+			t.Errorf("Expected no error. Got %s.", err)
+			continue
+		}
+		hunks := make([]string, 0)
+		for _, line := range strings.Split(diff, "\n") {
+			if strings.HasPrefix(line, "@@") {
+				hunks = append(hunks, line)
+			}
+		}
+		if len(hunks) != 1 || hunks[0] != expected {
+			t.Errorf("Expected the hunk header '%s' with %d context lines. Got %q.", expected, contextLines, hunks)
+		}
+	}
+}
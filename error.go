@@ -0,0 +1,108 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// capturedOutputSize is the maximum size of the output kept in an Error for a command displaying its output.
+const capturedOutputSize = 64 * 1024
+
+// Error is a git command which failed.
+type Error struct {
+	// Args is the command line, like ["git", "push", "origin"], with credentials masked.
+	Args     []string
+	ExitCode int
+	// Stdout and Stderr are the output of git. For commands displaying their output, like Push,
+	// only the beginning of the output is kept.
+	Stdout string
+	Stderr string
+}
+
+// Error returns the command line, the exit code and the error output of git.
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("'%s' failed with exit code %d", strings.Join(e.Args, " "), e.ExitCode)
+	if stderr := strings.Trim(e.Stderr, " \n"); stderr != "" {
+		msg += ": " + stderr
+	}
+	return msg
+}
+
+// newError returns an *Error for the command cmd if err is a git failure, err otherwise.
+func newError(cmd *exec.Cmd, err error, stdout, stderr []byte) error {
+	var gitErr *Error
+	if errors.As(err, &gitErr) {
+		// Returned by a Runner.
+		return err
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return err
+	}
+	if stderr == nil {
+		// Filled by cmd.Output.
+		stderr = exitErr.Stderr
+	}
+	return &Error{
+		Args:     redactCredentials(cmd.Args),
+		ExitCode: exitErr.ExitCode(),
+		Stdout:   string(stdout),
+		Stderr:   string(stderr),
+	}
+}
+
+// IsAuthError returns true if err is ErrAuthenticationFailed, or a git failure caused by the remote
+// refusing the credentials.
+func IsAuthError(err error) bool {
+	if errors.Is(err, ErrAuthenticationFailed) {
+		return true
+	}
+	var gitErr *Error
+	return errors.As(err, &gitErr) && authFailureRE.MatchString(gitErr.Stderr)
+}
+
+// IsNotARepo returns true if err is a git failure because the directory is not a git repository.
+func IsNotARepo(err error) bool {
+	var gitErr *Error
+	return errors.As(err, &gitErr) && gitErr.ExitCode == 128 &&
+		strings.Contains(strings.ToLower(gitErr.Stderr), "not a git repository")
+}
+
+// IsMergeConflict returns true if err is ErrConflict, or a git failure caused by conflicts,
+// like a merge, a rebase or a cherry-pick stopped to resolve them.
+func IsMergeConflict(err error) bool {
+	if errors.Is(err, ErrConflict) {
+		return true
+	}
+	var gitErr *Error
+	if !errors.As(err, &gitErr) || gitErr.ExitCode != 1 {
+		return false
+	}
+	for _, pattern := range []string{"CONFLICT (", "Automatic merge failed", "could not apply", "after resolving the conflicts"} {
+		if strings.Contains(gitErr.Stdout, pattern) || strings.Contains(gitErr.Stderr, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// limitedBuffer keeps the first max bytes written to it, and ignores the rest.
+type limitedBuffer struct {
+	bytes.Buffer
+	max int
+}
+
+// Write keeps the beginning of p which fits in the buffer. It never fails.
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.Len(); room > 0 {
+		if len(p) > room {
+			b.Buffer.Write(p[:room])
+		} else {
+			b.Buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
@@ -0,0 +1,128 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetError(t *testing.T) {
+	t.Log("Expecting Get to return an Error with the git command, exit code and error output.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	// Run the function
+	t.Log("Running Get(\"rev-parse\", \"--verify\", \"unknown\")...")
+	_, err := Get("rev-parse", "--verify", "unknown")
+
+	// Test the result
+	var gitErr *Error
+	if !errors.As(err, &gitErr) {
+		t.Fatalf("Expected an *Error. Got %#v.", err)
+	}
+	if expected := []string{"git", "rev-parse", "--verify", "unknown"}; !reflect.DeepEqual(gitErr.Args, expected) {
+		t.Errorf("Expected the command %q. Got %q.", expected, gitErr.Args)
+	}
+	if gitErr.ExitCode != 128 {
+		t.Errorf("Expected the exit code 128. Got %d.", gitErr.ExitCode)
+	}
+	if !strings.Contains(gitErr.Stderr, "fatal: Needed a single revision") {
+		t.Errorf("Expected the git error output. Got '%s'.", gitErr.Stderr)
+	}
+	if v := exitCode(fmt.Errorf("Unable to check. %w", err)); v != 128 {
+		t.Errorf("Expected exitCode to return 128. Got %d.", v)
+	}
+}
+
+func TestCommitError(t *testing.T) {
+	t.Log("Expecting Commit to return the git failure.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	writeFile(t, ".git/hooks/pre-commit", "#!/bin/sh\necho 'rejected by hook' >&2\nexit 1\n")
+	os.Chmod(".git/hooks/pre-commit", 0755)
+	writeFile(t, "file", "1")
+	runGit(t, "add", "file")
+
+	// Run the function
+	t.Log("Running Commit(\"first\", true) with a failing pre-commit hook...")
+	err := Commit("first", true)
+
+	// Test the result
+	var gitErr *Error
+	if !errors.As(err, &gitErr) {
+		t.Fatalf("Expected an *Error. Got %#v.", err)
+	}
+	if gitErr.ExitCode != 1 || !strings.Contains(gitErr.Stderr, "rejected by hook") {
+		t.Errorf("Expected the hook failure. Got %+v.", gitErr)
+	}
+}
+
+func TestErrorPredicates(t *testing.T) {
+	t.Log("Expecting IsNotARepo, IsMergeConflict and IsAuthError to identify git failures.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	runGit(t, "checkout", "-q", "-b", "feature")
+	commitFile(t, "file", "feature", "feature")
+	runGit(t, "checkout", "-q", "master")
+	commitFile(t, "file", "master", "master")
+	notARepo, err := ioutil.TempDir("", "go-git-test")
+	if err != nil {
+		t.Fatalf("Unable to create a temporary directory. %s", err)
+	}
+	defer os.RemoveAll(notARepo)
+
+	_, notARepoErr := NewRepo(notARepo).Get("status")
+	_, conflictErr := Get("merge", "feature")
+	_, otherErr := Get("rev-parse", "--verify", "unknown")
+	authErr := &Error{Args: []string{"git", "fetch"}, ExitCode: 128, Stderr: "fatal: Authentication failed for 'https://example.com/repo.git/'"}
+
+	tests := []struct {
+		name                          string
+		err                           error
+		notARepo, mergeConflict, auth bool
+	}{
+		{"not a repository", notARepoErr, true, false, false},
+		{"merge conflict", conflictErr, false, true, false},
+		{"authentication", fmt.Errorf("Unable to fetch. %w", authErr), false, false, true},
+		{"ErrConflict", ErrConflict, false, true, false},
+		{"ErrAuthenticationFailed", ErrAuthenticationFailed, false, false, true},
+		{"other git failure", otherErr, false, false, false},
+		{"not run", &exec.Error{Name: "git", Err: exec.ErrNotFound}, false, false, false},
+		{"no error", nil, false, false, false},
+	}
+	for _, test := range tests {
+		// Run the function
+		notARepo, mergeConflict, auth := IsNotARepo(test.err), IsMergeConflict(test.err), IsAuthError(test.err)
+
+		// Test the result
+		if notARepo != test.notARepo || mergeConflict != test.mergeConflict || auth != test.auth {
+			t.Errorf("Expected %s to be not a repo %t, a conflict %t, an auth error %t. Got %t, %t, %t.",
+				test.name, test.notARepo, test.mergeConflict, test.auth, notARepo, mergeConflict, auth)
+		}
+	}
+}
+
+func TestLimitedBuffer(t *testing.T) {
+	t.Log("Expecting limitedBuffer to keep the beginning of the output.")
+	b := &limitedBuffer{max: 5}
+
+	// Run the function
+	for _, text := range []string{"abc", "defg", "hij"} {
+		if n, err := b.Write([]byte(text)); n != len(text) || err != nil {
+			t.Errorf("Expected Write to accept %d bytes. Got %d, %v.", len(text), n, err)
+		}
+	}
+
+	// Test the result
+	if v := b.String(); v != "abcde" {
+		t.Errorf("Expected 'abcde'. Got '%s'.", v)
+	}
+}
@@ -0,0 +1,11 @@
+//go:build windows
+
+package git
+
+import (
+	"os/exec"
+)
+
+// killProcessGroup does nothing on windows: only git is killed when the command context is done.
+func killProcessGroup(cmd *exec.Cmd) {
+}
@@ -0,0 +1,213 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// IgnoreMatch identifies the ignore rule matching a path.
+type IgnoreMatch struct {
+	Source  string
+	Line    int
+	Pattern string
+	Path    string
+}
+
+// TrackedStatus returns for each path given if it is tracked by git.
+// Only one git command is run whatever the number of paths.
+func (r *Repo) TrackedStatus(paths []string) (map[string]bool, error) {
+	status := make(map[string]bool, len(paths))
+	if len(paths) == 0 {
+		return status, nil
+	}
+
+	opts := append([]string{"ls-files", "-z", "--"}, paths...)
+	v, err := r.getRaw(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	tracked := make(map[string]bool)
+	for _, file := range strings.Split(v, "\x00") {
+		if file != "" {
+			tracked[file] = true
+		}
+	}
+	for _, aPath := range paths {
+		status[aPath] = tracked[filepath.ToSlash(filepath.Clean(aPath))]
+	}
+	return status, nil
+}
+
+// PathDirty returns true if path has uncommitted changes, staged or not, or is untracked.
+// For a directory, any change under it makes it dirty.
+func (r *Repo) PathDirty(path string) (bool, error) {
+	v, err := r.Get("status", "--porcelain", "--", path)
+	if err != nil {
+		return false, err
+	}
+	return v != "", nil
+}
+
+// IgnoreSources returns the ignore rule which makes path ignored.
+// The list is empty if the path is not ignored.
+func (r *Repo) IgnoreSources(path string) ([]IgnoreMatch, error) {
+	matches := make([]IgnoreMatch, 0)
+	v, err := r.Get("check-ignore", "-v", "--", path)
+	if exitCode(err) == 1 {
+		return matches, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Unable to check if '%s' is ignored. %s", path, err)
+	}
+
+	// <source>:<linenum>:<pattern> TAB <pathname>
+	matchRE, _ := regexp.Compile(`^(.*?):(\d+):(.*)\t(.*)$`)
+	for _, line := range strings.Split(v, "\n") {
+		m := matchRE.FindStringSubmatch(line)
+		if m == nil || strings.HasPrefix(m[3], "!") {
+			// A negated pattern means the path is not ignored.
+			continue
+		}
+		lineNum, _ := strconv.Atoi(m[2])
+		matches = append(matches, IgnoreMatch{Source: m[1], Line: lineNum, Pattern: m[3], Path: m[4]})
+	}
+	return matches, nil
+}
+
+// PathState is the state of a path in the working tree, returned by GetPathState.
+type PathState int
+
+// Values of PathState
+const (
+	PathClean PathState = iota
+	PathIgnored
+	PathUntracked
+	PathStaged
+	PathModified
+	PathMissing
+)
+
+var pathStateNames = []string{"clean", "ignored", "untracked", "staged", "modified", "missing"}
+
+// String returns the name of the state, like "modified".
+func (s PathState) String() string {
+	if s < 0 || int(s) >= len(pathStateNames) {
+		return "unknown"
+	}
+	return pathStateNames[s]
+}
+
+// GetPathState returns the state of path using a single git status call:
+// - PathMissing: the path does not exist in the working tree, if tracked or not.
+// - PathModified: the working tree differs from the index, even if some changes are staged.
+// - PathStaged: changes, including a removal, are staged and the working tree matches the index.
+// - PathUntracked, PathIgnored, or PathClean otherwise.
+// For a directory, the most significant state of its files is returned, in the reverse order of the list above.
+func (r *Repo) GetPathState(path string) (PathState, error) {
+	v, err := r.getRaw("status", "--porcelain", "--ignored", "--", path)
+	if err != nil {
+		return PathClean, fmt.Errorf("Unable to get the status of '%s'. %s", path, err)
+	}
+	v = strings.TrimRight(v, "\n")
+	if v == "" {
+		if _, err := os.Stat(r.join(path)); os.IsNotExist(err) {
+			return PathMissing, nil
+		}
+		return PathClean, nil
+	}
+
+	state := PathClean
+	for _, line := range strings.Split(v, "\n") {
+		if len(line) < 3 {
+			continue
+		}
+		lineState := PathClean
+		switch ready, notReady := line[0], line[1]; {
+		case ready == '?':
+			lineState = PathUntracked
+		case ready == '!':
+			lineState = PathIgnored
+		case notReady == 'D':
+			lineState = PathMissing
+		case notReady != ' ':
+			lineState = PathModified
+		case ready != ' ':
+			lineState = PathStaged
+		}
+		if lineState > state {
+			state = lineState
+		}
+	}
+	return state, nil
+}
+
+// MissingTrackedFiles returns the files of the index which do not exist in the working tree,
+// ie removed from the disk but not staged for removal.
+// Files excluded by a sparse checkout are not reported.
+func (r *Repo) MissingTrackedFiles() ([]string, error) {
+	v, err := r.Get("ls-files", "-z", "-t")
+	if err != nil {
+		return nil, fmt.Errorf("Unable to list the tracked files. %s", err)
+	}
+
+	missing := make([]string, 0)
+	for _, entry := range strings.Split(v, "\x00") {
+		// <tag> SP <file>
+		if len(entry) < 3 || entry[0] == 'S' {
+			continue
+		}
+		file := entry[2:]
+		if _, err := os.Lstat(r.join(file)); os.IsNotExist(err) {
+			missing = append(missing, file)
+		} else if err != nil {
+			return missing, err
+		}
+	}
+	return missing, nil
+}
+
+// TrackedFile is a tracked file and its size in the working tree.
+type TrackedFile struct {
+	Path string
+	Size int64
+}
+
+// LargestTrackedFiles returns the n largest tracked files of the working tree, the largest first.
+// If n is 0 or less, all tracked files are returned. Files missing from the disk are ignored,
+// as well as entries which are not regular files, like submodules and symbolic links.
+func (r *Repo) LargestTrackedFiles(n int) ([]TrackedFile, error) {
+	v, err := r.getRaw("ls-files", "-z")
+	if err != nil {
+		return nil, fmt.Errorf("Unable to list the tracked files. %s", err)
+	}
+
+	files := make([]TrackedFile, 0)
+	for _, file := range strings.Split(v, "\x00") {
+		if file == "" {
+			continue
+		}
+		fi, err := os.Lstat(r.join(file))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if fi.Mode().IsRegular() {
+			files = append(files, TrackedFile{Path: file, Size: fi.Size()})
+		}
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].Size > files[j].Size
+	})
+	if n > 0 && len(files) > n {
+		files = files[:n]
+	}
+	return files, nil
+}
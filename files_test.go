@@ -0,0 +1,223 @@
+package git
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestTrackedStatus(t *testing.T) {
+	t.Log("Expecting TrackedStatus to report tracked and untracked paths.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "tracked", "1", "first")
+	commitFile(t, "dir/tracked", "1", "second")
+	commitFile(t, " leading space", "1", "third")
+	writeFile(t, "untracked", "1")
+	writeFile(t, "dir/untracked", "1")
+
+	// Run the function
+	paths := []string{"tracked", "./dir/tracked", " leading space", "untracked", "dir/untracked", "missing"}
+	t.Logf("Running TrackedStatus(%v)...", paths)
+	status, err := TrackedStatus(paths)
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	expected := map[string]bool{
+		"tracked":        true,
+		"./dir/tracked":  true,
+		" leading space": true,
+		"untracked":      false,
+		"dir/untracked":  false,
+		"missing":        false,
+	}
+	for aPath, value := range expected {
+		if v, found := status[aPath]; !found {
+			t.Errorf("Expected '%s' to be reported. Not found.", aPath)
+		} else if v != value {
+			t.Errorf("Expected '%s' tracked status to be %t. Got %t.", aPath, value, v)
+		}
+	}
+}
+
+func TestPathDirty(t *testing.T) {
+	t.Log("Expecting PathDirty to detect uncommitted changes of a path.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "clean", "1", "first")
+	commitFile(t, "staged", "1", "second")
+	commitFile(t, "unstaged", "1", "third")
+	writeFile(t, "staged", "2")
+	runGit(t, "add", "staged")
+	writeFile(t, "unstaged", "2")
+	writeFile(t, "untracked", "1")
+
+	tests := map[string]bool{
+		"clean":     false,
+		"staged":    true,
+		"unstaged":  true,
+		"untracked": true,
+	}
+	for aPath, expected := range tests {
+		// Run the function
+		t.Logf("Running PathDirty(%q)...", aPath)
+		dirty, err := PathDirty(aPath)
+
+		// Test the result
+		if err != nil {
+			t.Errorf("Expected no error. Got %s.", err)
+		} else if dirty != expected {
+			t.Errorf("Expected '%s' dirty to be %t. Got %t.", aPath, expected, dirty)
+		}
+	}
+}
+
+func TestIgnoreSources(t *testing.T) {
+	t.Log("Expecting IgnoreSources to report the rule ignoring a path.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	writeFile(t, ".gitignore", "*.tmp\n")
+	writeFile(t, "dir/.gitignore", "# logs\n*.log\n!keep.log\n")
+
+	// Run the function
+	t.Log("Running IgnoreSources(\"dir/app.log\")...")
+	matches, err := IgnoreSources("dir/app.log")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	expected := IgnoreMatch{Source: "dir/.gitignore", Line: 2, Pattern: "*.log", Path: "dir/app.log"}
+	if len(matches) != 1 || matches[0] != expected {
+		t.Errorf("Expected [%+v]. Got %+v.", expected, matches)
+	}
+
+	for _, aPath := range []string{"dir/app.txt", "dir/keep.log"} {
+		// Run the function
+		t.Logf("Running IgnoreSources(%q)...", aPath)
+		matches, err = IgnoreSources(aPath)
+
+		// Test the result
+		if err != nil {
+			t.Errorf("Expected no error. Got %s.", err)
+		} else if len(matches) != 0 {
+			t.Errorf("Expected no rule for '%s'. Got %+v.", aPath, matches)
+		}
+	}
+}
+
+func TestGetPathState(t *testing.T) {
+	t.Log("Expecting GetPathState to return the state of each path.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, ".gitignore", "*.log\n", "ignore")
+	for _, file := range []string{"clean", "modified", "staged", "both", "deleted", "dir/clean"} {
+		commitFile(t, file, "1", file)
+	}
+	writeFile(t, "modified", "2")
+	writeFile(t, "staged", "2")
+	runGit(t, "add", "staged")
+	writeFile(t, "both", "2")
+	runGit(t, "add", "both")
+	writeFile(t, "both", "3")
+	os.Remove("deleted")
+	writeFile(t, "untracked", "1")
+	writeFile(t, "debug.log", "1")
+	writeFile(t, "dir/new", "1")
+
+	tests := map[string]PathState{
+		"clean":     PathClean,
+		"modified":  PathModified,
+		"staged":    PathStaged,
+		"both":      PathModified,
+		"deleted":   PathMissing,
+		"unknown":   PathMissing,
+		"untracked": PathUntracked,
+		"debug.log": PathIgnored,
+		"dir":       PathUntracked,
+	}
+	for path, expected := range tests {
+		// Run the function
+		state, err := GetPathState(path)
+
+		// Test the result
+		if err != nil {
+			t.Errorf("Expected no error. Got %s.", err)
+		} else if state != expected {
+			t.Errorf("Expected '%s' to be %s. Got %s.", path, expected, state)
+		}
+	}
+}
+
+func TestMissingTrackedFiles(t *testing.T) {
+	t.Log("Expecting MissingTrackedFiles to report tracked files removed from the disk only.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	for _, file := range []string{"kept", "missing", "dir/missing", "removed", "sparse"} {
+		commitFile(t, file, "1", file)
+	}
+	os.Remove("missing")
+	os.RemoveAll("dir")
+	runGit(t, "rm", "-q", "removed")
+	runGit(t, "update-index", "--skip-worktree", "sparse")
+	os.Remove("sparse")
+
+	// Run the function
+	t.Log("Running MissingTrackedFiles()...")
+	missing, err := MissingTrackedFiles()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if expected := []string{"dir/missing", "missing"}; !reflect.DeepEqual(missing, expected) {
+		t.Errorf("Expected %q. Got %q.", expected, missing)
+	}
+}
+
+func TestLargestTrackedFiles(t *testing.T) {
+	t.Log("Expecting LargestTrackedFiles to return the largest tracked files first.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	writeFile(t, "small", "1")
+	writeFile(t, " leading space", "12")
+	writeFile(t, "dir/large", "1234567890")
+	writeFile(t, "medium", "12345")
+	writeFile(t, "removed", "123456789012345")
+	runGit(t, "add", ".")
+	runGit(t, "commit", "-q", "-m", "first")
+	os.Remove("removed")
+	writeFile(t, "untracked", "12345678901234567890")
+
+	// Run the function
+	t.Log("Running LargestTrackedFiles(2)...")
+	files, err := LargestTrackedFiles(2)
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if expected := []TrackedFile{{"dir/large", 10}, {"medium", 5}}; !reflect.DeepEqual(files, expected) {
+		t.Errorf("Expected %v. Got %v.", expected, files)
+	}
+
+	// Run the function
+	t.Log("Running LargestTrackedFiles(0)...")
+	files, err = LargestTrackedFiles(0)
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if expected := []TrackedFile{{"dir/large", 10}, {"medium", 5}, {" leading space", 2}, {"small", 1}}; !reflect.DeepEqual(files, expected) {
+		t.Errorf("Expected %v. Got %v.", expected, files)
+	}
+}
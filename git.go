@@ -1,8 +1,11 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/forj-oss/forjj/utils"
+	"io"
 	"log"
 	"os"
 	"os/exec"
@@ -18,7 +21,7 @@ type gitContext struct {
 	indent string
 }
 
-var context gitContext
+var gitCtx gitContext
 var logFunc func(string)
 
 func init() {
@@ -34,25 +37,74 @@ func SetLogFunc(aLogFunc func(string)) {
 	logFunc = aLogFunc
 }
 
-// Do Call git command with arguments. All print out displayed. It returns git Return code.
-func Do(opts ...string) int {
+// logCommand displays a git command. Credentials given to git are masked.
+func logCommand(opts []string) {
 	colorCyan, colorReset := utils.DefColor(36)
-	logFunc(fmt.Sprintf("%s%sgit %s%s\n", colorCyan, context.indent, strings.Join(opts, " "), colorReset))
-	return utils.RunCmd("git", opts...)
+	logFunc(fmt.Sprintf("%s%sgit %s%s\n", colorCyan, gitCtx.indent, strings.Join(redactCredentials(opts), " "), colorReset))
+}
+
+// Do Call git command with arguments. All print out displayed. It returns git Return code.
+func (r *Repo) Do(opts ...string) int {
+	return r.doWithEnv(nil, opts...)
+}
+
+// DoContext Call git command like Do. git and the processes it started are killed when ctx is done.
+// It returns git Return code, or an error if git could not be run or if the context is done:
+// ctx.Err() is returned on cancellation or timeout.
+func (r *Repo) DoContext(ctx context.Context, opts ...string) (int, error) {
+	return r.doContext(ctx, nil, opts...)
+}
+
+// doWithEnv Call git command like Do, with additional environment variables set as "KEY=value".
+func (r *Repo) doWithEnv(env []string, opts ...string) int {
+	code, err := r.doContext(context.Background(), env, opts...)
+	if err != nil {
+		gotrace.Error("Unable to run git. %s", err)
+		return 1
+	}
+	return code
+}
+
+// doContext Call git command like DoContext, with additional environment variables set as "KEY=value".
+func (r *Repo) doContext(ctx context.Context, env []string, opts ...string) (int, error) {
+	logCommand(r.args(opts))
+	cmd := r.commandContext(ctx, opts...)
+	addEnv(cmd, env)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err := r.runCommand(cmd)
+	if ctx.Err() != nil {
+		return -1, ctx.Err()
+	}
+	if code := exitCode(err); code >= 0 {
+		return code, nil
+	}
+	return -1, err
+}
+
+// doError Call git command like Do, and returns an *Error with the beginning of the git output if git fails.
+func (r *Repo) doError(opts ...string) error {
+	logCommand(r.args(opts))
+	cmd := r.command(opts...)
+	stdout := &limitedBuffer{max: capturedOutputSize}
+	stderr := &limitedBuffer{max: capturedOutputSize}
+	cmd.Stdout = io.MultiWriter(os.Stdout, stdout)
+	cmd.Stderr = io.MultiWriter(os.Stderr, stderr)
+	return newError(cmd, r.runCommand(cmd), stdout.Bytes(), stderr.Bytes())
 }
 
 // Indent permit to display several command indented within a section tag.
 func Indent(begin, indent, end string) {
 	colorCyan, colorReset := utils.DefColor(36)
 	logFunc(fmt.Sprintf("%s%s%s\n", colorCyan, begin, colorReset))
-	context.end = end
-	context.indent = indent
+	gitCtx.end = end
+	gitCtx.indent = indent
 }
 
 // UnIndent revert Indent.
 func UnIndent() {
 	colorCyan, colorReset := utils.DefColor(36)
-	logFunc(fmt.Sprintf("%s%s%s\n", colorCyan, context.end, colorReset))
+	logFunc(fmt.Sprintf("%s%s%s\n", colorCyan, gitCtx.end, colorReset))
 }
 
 // ShowGitPath display the current GI path
@@ -67,85 +119,168 @@ func ShowGitPath() (msg string) {
 }
 
 // GetStatus return an GitStatus struct with the list of files, added, updated and
-func GetStatus() (gs *Status) {
+// removed in the ready area (index) and in the not ready area (working tree).
+// A file staged then updated again is listed in both. Untracked files are in the not ready area.
+// Renamed and copied files, conflicts and submodules are described in Status.Entries.
+func (r *Repo) GetStatus() (gs *Status) {
 	gs = new(Status)
 
 	gs.Ready = make(map[string][]string)
 	gs.Ready.init(false)
 	gs.NotReady = make(map[string][]string)
 	gs.NotReady.init(true)
-
-	ReadyRE, _ := regexp.Compile("^([ADM])  (.*)$")
-	NotReadyRE, _ := regexp.Compile("^ ([?ADM]) (.*)$")
+	gs.Entries = make([]StatusEntry, 0)
 
 	var s string
 
-	s, gs.Err = Get("status", "--porcelain")
-	if gs.Err != nil || s == "" {
+	s, gs.Err = r.getRaw("status", "--porcelain=v2", "-z")
+	if gs.Err != nil {
 		return
 	}
+	gs.Err = gs.parsePorcelainV2(s)
+	return
+}
 
-	lines := strings.Split(s, "\n")
-
-	for _, line := range lines {
-		if m := ReadyRE.FindStringSubmatch(line); m != nil {
-			gs.Ready.add(m[1], m[2])
-		}
-		if m := NotReadyRE.FindStringSubmatch(line); m != nil {
-			gs.Ready.add(m[1], m[2])
-		}
+// StatusShort returns the lines of git status in short format, as displayed by git.
+func (r *Repo) StatusShort() ([]string, error) {
+	v, err := r.getRaw("status", "--short")
+	v = strings.TrimRight(v, "\n")
+	if err != nil || v == "" {
+		return []string{}, err
 	}
-	return
+	return strings.Split(v, "\n"), nil
 }
 
 // Get Call a git command and get the output as string output.
-func Get(opts ...string) (string, error) {
-	gotrace.Trace("RUNNING: git %s", strings.Join(opts, " "))
-	out, err := exec.Command("git", opts...).Output()
-	return strings.Trim(string(out), " \n"), err
+func (r *Repo) Get(opts ...string) (string, error) {
+	return r.GetContext(context.Background(), opts...)
+}
+
+// GetContext Call a git command like Get. git and the processes it started are killed when ctx is done,
+// and ctx.Err() is returned instead of the git exit error.
+func (r *Repo) GetContext(ctx context.Context, opts ...string) (string, error) {
+	out, err := r.getRawContext(ctx, opts...)
+	return strings.Trim(out, " \n"), err
+}
+
+// getRaw Call a git command and get the output as is.
+func (r *Repo) getRaw(opts ...string) (string, error) {
+	return r.getRawContext(context.Background(), opts...)
+}
+
+// getRawContext Call a git command and get the output as is. ctx.Err() is returned if ctx is done.
+func (r *Repo) getRawContext(ctx context.Context, opts ...string) (string, error) {
+	cmd := r.commandContext(ctx, opts...)
+	out, err := r.outputCommand(cmd)
+	if ctx.Err() != nil {
+		return string(out), ctx.Err()
+	}
+	return string(out), newError(cmd, err, out, nil)
+}
+
+// getWithInput Call a git command with input sent to its standard input and get the output as string output.
+func (r *Repo) getWithInput(input string, opts ...string) (string, error) {
+	cmd := r.command(opts...)
+	cmd.Stdin = strings.NewReader(input)
+	out, err := r.outputCommand(cmd)
+	return strings.Trim(string(out), " \n"), newError(cmd, err, out, nil)
+}
+
+// exitCode returns the exit code of a failed git command, 0 if err is nil, or -1 if git was not run.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var gitErr *Error
+	if errors.As(err, &gitErr) {
+		return gitErr.ExitCode
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
 }
 
 // GetWithStatusCode Call a git command and get the output as string output.
-func GetWithStatusCode(opts ...string) (string, int) {
-	colorCyan, colorReset := utils.DefColor(36)
-	logFunc(fmt.Sprintf("%s%sgit %s%s\n", colorCyan, context.indent, strings.Join(opts, " "), colorReset))
-	return utils.RunCmdOutput("git", opts...)
+func (r *Repo) GetWithStatusCode(opts ...string) (string, int) {
+	logCommand(r.args(opts))
+	cmd := r.command(opts...)
+	cmd.Stderr = os.Stderr
+	out, err := r.outputCommand(cmd)
+	return strings.Trim(string(out), " \n"), exitCode(err)
 }
 
 // Commit Do a git commit
-func Commit(msg string, errorIfEmpty bool) (err error) {
-	s := GetStatus()
+func (r *Repo) Commit(msg string, errorIfEmpty bool) (err error) {
+	s := r.GetStatus()
 	if s.Ready.CountTracked() == 0 {
 		if errorIfEmpty {
 			err = fmt.Errorf("No files to commit. Please check")
 		}
 		return
 	}
-	if Do("commit", "-m", msg) > 0 {
-		return fmt.Errorf("Unable to commit")
+	if err := r.doError("commit", "-m", msg); err != nil {
+		return fmt.Errorf("Unable to commit. %w", err)
 	}
 	return nil
 }
 
 // Push Push latest commits
-func Push() error {
-	if Do("push") > 0 {
-		return fmt.Errorf("Unable to push commits")
+// Commits are pushed to Repo.DefaultRemote if set, or as configured in git otherwise.
+func (r *Repo) Push() error {
+	opts := []string{"push"}
+	if r.DefaultRemote != "" {
+		opts = append(opts, r.DefaultRemote)
+	}
+	if err := r.doError(r.withCredentials(opts...)...); err != nil {
+		return fmt.Errorf("Unable to push commits. %w", err)
 	}
 	return nil
 }
 
 // Add call git add
-func Add(files []string) int {
+func (r *Repo) Add(files []string) int {
 	cmd := make([]string, 1, len(files)+1)
 	cmd[0] = "add"
 	cmd = append(cmd, files...)
-	return Do(cmd...)
+	return r.Do(cmd...)
+}
+
+// AddPathspec call git add on the paths include, except the paths exclude.
+// Paths can be glob patterns, like "*.go". Without include, all files except exclude are added.
+func (r *Repo) AddPathspec(include []string, exclude []string) error {
+	opts := append([]string{"add", "--"}, include...)
+	for _, file := range exclude {
+		opts = append(opts, ":(exclude)"+file)
+	}
+	if len(include) == 0 && len(exclude) == 0 {
+		opts = append(opts, ".")
+	}
+	if r.Do(opts...) > 0 {
+		return fmt.Errorf("Unable to add %s", strings.Join(opts[2:], " "))
+	}
+	return nil
+}
+
+// AddPath call git add on a directory. New, updated and removed files under it are staged.
+// Ignored files are not added.
+func (r *Repo) AddPath(dir string) int {
+	return r.Do("add", "--all", "--", dir)
+}
+
+// RenormalizeLineEndings stages all tracked files again, to apply the line endings
+// conversion defined by .gitattributes to the index.
+func (r *Repo) RenormalizeLineEndings() error {
+	if r.Do("add", "--renormalize", ".") > 0 {
+		return fmt.Errorf("Unable to renormalize line endings")
+	}
+	return nil
 }
 
 // Branches retrieved the list of branch from git branch
-func Branches() ([]string, error) {
-	v, err := Get("branch")
+func (r *Repo) Branches() ([]string, error) {
+	v, err := r.Get("branch")
 	if err != nil || v == "" {
 		return []string{}, err
 	}
@@ -154,8 +289,8 @@ func Branches() ([]string, error) {
 
 // RemoteBranches returns the list of Remote branches found
 // Formatted as <remote>/<branchName>
-func RemoteBranches() ([]string, error) {
-	v, err := Get("branch", "-r")
+func (r *Repo) RemoteBranches() ([]string, error) {
+	v, err := r.Get("branch", "-r")
 	if err != nil || v == "" {
 		return []string{}, err
 	}
@@ -165,8 +300,8 @@ func RemoteBranches() ([]string, error) {
 // RemoteBranchExist check is remote branch if known by GIT.
 //
 // Remote: Formated as <remote>/<branchName>
-func RemoteBranchExist(remote string) (bool, error) {
-	branches, err := RemoteBranches()
+func (r *Repo) RemoteBranchExist(remote string) (bool, error) {
+	branches, err := r.RemoteBranches()
 	if err != nil {
 		return false, err
 	}
@@ -180,8 +315,8 @@ func RemoteBranchExist(remote string) (bool, error) {
 }
 
 // BranchExist return true if the branch exist
-func BranchExist(remote string) (bool, error) {
-	branches, err := Branches()
+func (r *Repo) BranchExist(remote string) (bool, error) {
+	branches, err := r.Branches()
 	if err != nil {
 		return false, err
 	}
@@ -195,19 +330,27 @@ func BranchExist(remote string) (bool, error) {
 }
 
 // RemoteStatus provide a sync status information
-func RemoteStatus(remote string) (_ string, err error) {
+// remote is formatted as <remote>/<branchName>. If empty, the current branch of the default remote is used.
+// See Repo.DefaultRemote.
+func (r *Repo) RemoteStatus(remote string) (_ string, err error) {
 	var localRev, remoteRev, baseRev string
-	localRev, err = Get("rev-parse", "@{0}")
+	if remote == "" {
+		if remote, err = r.resolveRemote(""); err != nil {
+			return
+		}
+		remote += "/" + r.GetCurrentBranch()
+	}
+	localRev, err = r.Get("rev-parse", "@{0}")
 	if err != nil {
 		return
 	}
 
-	remoteRev, err = Get("rev-parse", remote)
+	remoteRev, err = r.Get("rev-parse", remote)
 	if err != nil {
 		return
 	}
 
-	baseRev, err = Get("merge-base", "@{0}", remote)
+	baseRev, err = r.Get("merge-base", "@{0}", remote)
 	if err != nil {
 		return
 	}
@@ -225,9 +368,9 @@ func RemoteStatus(remote string) (_ string, err error) {
 }
 
 // RemoteExist return true if remote is defined.
-func RemoteExist(remote string) (found bool) {
+func (r *Repo) RemoteExist(remote string) (found bool) {
 	var remotes []string
-	v, err := Get("remote")
+	v, err := r.Get("remote")
 	if err != nil {
 		return
 	}
@@ -242,47 +385,55 @@ func RemoteExist(remote string) (found bool) {
 	return
 }
 
-// RemoteURL returns the url of the remote requested.
-func RemoteURL(remote string) (string, bool, error) {
-	var remotes []string
-	v, err := Get("remote", "-v")
-	if err != nil {
-		return "", false, err
-	}
+// RemoteURL returns the fetch url of the remote requested.
+func (r *Repo) RemoteURL(remote string) (string, bool, error) {
+	return r.remoteURL(remote, "fetch")
+}
 
-	if v == "" {
-		remotes = []string{}
-	} else {
-		remotes = strings.Split(v, "\n")
+// RemotePushURL returns the push url of the remote requested.
+// It is the fetch url, unless a push url is configured.
+func (r *Repo) RemotePushURL(remote string) (string, bool, error) {
+	return r.remoteURL(remote, "push")
+}
+
+// remoteURL returns the url of the remote used to fetch or push, as displayed by git remote -v.
+func (r *Repo) remoteURL(remote, direction string) (string, bool, error) {
+	v, err := r.Get("remote", "-v")
+	if err != nil || v == "" {
+		return "", false, err
 	}
 
-	remMatch, _ := regexp.Compile(`^ *(\w+)[ \t]*(.*) \((fetch)\)$`)
-	for _, aRemote := range remotes {
-		if v := remMatch.FindStringSubmatch(aRemote); v != nil && v[1] == remote {
-			return v[1], true, nil
+	remMatch, _ := regexp.Compile(`^(\S+)\t(.*) \((fetch|push)\)$`)
+	for _, aRemote := range strings.Split(v, "\n") {
+		m := remMatch.FindStringSubmatch(aRemote)
+		if m == nil {
+			continue
+		}
+		if m[1] == remote && m[3] == direction {
+			return m[2], true, nil
 		}
 	}
 	return "", false, nil
 }
 
 // EnsureRemoteIs will update the remote name with the url...
-func EnsureRemoteIs(name, url string) error {
-	if ru, found, err := RemoteURL(name); err != nil {
+func (r *Repo) EnsureRemoteIs(name, url string) error {
+	if ru, found, err := r.RemoteURL(name); err != nil {
 		return err
 	} else if found {
 		if ru != url {
-			Do("remote", "set-url", name, url)
+			r.Do("remote", "set-url", name, url)
 		}
 	} else {
-		Do("remote", "add", name, url)
+		r.Do("remote", "add", name, url)
 	}
 	return nil
 }
 
 // GetCurrentBranch return the current branch name.
 // If no branch is detected, it returns "master"
-func GetCurrentBranch() (branch string) {
-	b, status := GetWithStatusCode("rev-parse", "--abbrev-ref", "HEAD")
+func (r *Repo) GetCurrentBranch() (branch string) {
+	b, status := r.GetWithStatusCode("rev-parse", "--abbrev-ref", "HEAD")
 	if status == 128 {
 		return "master"
 	}
@@ -291,18 +442,32 @@ func GetCurrentBranch() (branch string) {
 	return
 }
 
-// EnsureRepoExist ensure a local repo exist.
-func EnsureRepoExist(aPath string) error {
+// IsBare returns true if the repository is bare, ie without working tree.
+func (r *Repo) IsBare() (bool, error) {
+	v, err := r.Get("rev-parse", "--is-bare-repository")
+	if err != nil {
+		return false, fmt.Errorf("Unable to check if the repository is bare. %s", err)
+	}
+	return v == "true", nil
+}
+
+// EnsureRepoExist ensure a local repo exist and returns it.
+// A new repository is created with the branch returned by DefaultInitBranch.
+func EnsureRepoExist(aPath string) (*Repo, error) {
 	if fi, err := os.Stat(path.Join(aPath, ".git")); err != nil && os.IsNotExist(err) {
-		if Do("init", aPath) != 0 {
-			return fmt.Errorf("Unable to create the local repository '%s'", aPath)
+		branch, err := DefaultInitBranch()
+		if err != nil {
+			return nil, fmt.Errorf("Unable to determine the default branch. %s", err)
+		}
+		if Do("init", "--initial-branch="+branch, aPath) != 0 {
+			return nil, fmt.Errorf("Unable to create the local repository '%s'", aPath)
 		}
 	} else if err != nil {
-		return err
+		return nil, err
 	} else if !fi.IsDir() {
-		return fmt.Errorf("'%s' is not a valid GIT repo (.git is not a directory)", aPath)
+		return nil, fmt.Errorf("'%s' is not a valid GIT repo (.git is not a directory)", aPath)
 	}
-	return nil
+	return defaultRepo.repoAt(aPath), nil
 }
 
 // RunInPath run a function in a specificDirectory and restore the current Path.
@@ -0,0 +1,254 @@
+package git
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAddPath(t *testing.T) {
+	t.Log("Expecting AddPath to stage all changes under a directory.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "out/removed", "removed", "first")
+	commitFile(t, "out/updated", "1", "second")
+	writeFile(t, "out/.gitignore", "*.tmp\n")
+	os.Remove("out/removed")
+	writeFile(t, "out/updated", "2")
+	writeFile(t, "out/sub/added", "added")
+	writeFile(t, "out/cache.tmp", "1")
+	writeFile(t, "outside", "1")
+
+	// Run the function
+	t.Log("Running AddPath(\"out\")...")
+	if v := AddPath("out"); v != 0 {
+		t.Fatalf("Expected git add to succeed. Got %d.", v)
+	}
+
+	// Test the result
+	expected := "A  out/.gitignore\nD  out/removed\nA  out/sub/added\nM  out/updated\n?? outside"
+	if v := runGit(t, "status", "--porcelain"); v != expected {
+		t.Errorf("Expected status:\n%s\nGot:\n%s", expected, v)
+	}
+}
+
+func TestAddPathspec(t *testing.T) {
+	t.Log("Expecting AddPathspec to stage a directory except the excluded files.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	writeFile(t, "out/config", "1")
+	writeFile(t, "out/secret.key", "1")
+	writeFile(t, "out/sub/data", "1")
+	writeFile(t, "out/sub/other.key", "1")
+	writeFile(t, "outside", "1")
+
+	// Run the function
+	t.Log("Running AddPathspec([out], [out/secret.key out/sub/*.key])...")
+	err := AddPathspec([]string{"out"}, []string{"out/secret.key", "out/sub/*.key"})
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	expected := "A  out/config\nA  out/sub/data\n?? out/secret.key\n?? out/sub/other.key\n?? outside"
+	if v := runGit(t, "status", "--porcelain", "--untracked-files=all"); v != expected {
+		t.Errorf("Expected status:\n%s\nGot:\n%s", expected, v)
+	}
+}
+
+func TestStatusShort(t *testing.T) {
+	t.Log("Expecting StatusShort to return git status short lines.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	// Run the function
+	t.Log("Running StatusShort() on a clean tree...")
+	if lines, err := StatusShort(); err != nil {
+		t.Errorf("Expected no error. Got %s.", err)
+	} else if len(lines) != 0 {
+		t.Errorf("Expected no lines. Got %q.", lines)
+	}
+
+	commitFile(t, "modified", "1", "first")
+	commitFile(t, "staged", "1", "second")
+	writeFile(t, "modified", "2")
+	writeFile(t, "staged", "2")
+	runGit(t, "add", "staged")
+	writeFile(t, "untracked", "1")
+
+	// Run the function
+	t.Log("Running StatusShort()...")
+	lines, err := StatusShort()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	expected := []string{" M modified", "M  staged", "?? untracked"}
+	if v := strings.Join(lines, "|"); v != strings.Join(expected, "|") {
+		t.Errorf("Expected %q. Got %q.", expected, lines)
+	}
+}
+
+func TestRenormalizeLineEndings(t *testing.T) {
+	t.Log("Expecting RenormalizeLineEndings to normalize and stage line endings.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	runGit(t, "config", "core.autocrlf", "false")
+	commitFile(t, "crlf.txt", "line1\r\nline2\r\n", "crlf")
+	commitFile(t, "mixed.txt", "line1\nline2\r\n", "mixed")
+	commitFile(t, "lf.txt", "line1\nline2\n", "lf")
+	commitFile(t, ".gitattributes", "* text=auto\n", "attributes")
+
+	// Run the function
+	t.Log("Running RenormalizeLineEndings()...")
+	if err := RenormalizeLineEndings(); err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+
+	// Test the result
+	if v := runGit(t, "diff", "--cached", "--name-only"); v != "crlf.txt\nmixed.txt" {
+		t.Errorf("Expected 'crlf.txt' and 'mixed.txt' to be staged. Got '%s'.", v)
+	}
+	for _, file := range []string{"crlf.txt", "mixed.txt", "lf.txt"} {
+		if v := runGit(t, "ls-files", "--eol", file); !strings.HasPrefix(v, "i/lf ") {
+			t.Errorf("Expected '%s' to have LF line endings in the index. Got '%s'.", file, v)
+		}
+	}
+}
+
+func TestRemoteURL(t *testing.T) {
+	t.Log("Expecting RemoteURL and RemotePushURL to return the url of the remote requested.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	runGit(t, "remote", "add", "origin", "https://example.com/origin.git")
+	runGit(t, "remote", "add", "my-fork", "https://example.com/fork.git")
+	runGit(t, "remote", "set-url", "--push", "my-fork", "git@example.com:fork.git")
+
+	tests := []struct {
+		remote string
+		push   bool
+		url    string
+		found  bool
+	}{
+		{"origin", false, "https://example.com/origin.git", true},
+		{"origin", true, "https://example.com/origin.git", true},
+		{"my-fork", false, "https://example.com/fork.git", true},
+		{"my-fork", true, "git@example.com:fork.git", true},
+		{"unknown", false, "", false},
+	}
+	for _, test := range tests {
+		// Run the function
+		getURL := RemoteURL
+		if test.push {
+			getURL = RemotePushURL
+		}
+		url, found, err := getURL(test.remote)
+
+		// Test the result
+		if err != nil {
+			t.Errorf("Expected no error. Got %s.", err)
+		} else if url != test.url || found != test.found {
+			t.Errorf("Expected '%s' url (push %t) to be '%s', %t. Got '%s', %t.", test.remote, test.push, test.url, test.found, url, found)
+		}
+	}
+
+	// Run the function
+	t.Log("Running EnsureRemoteIs(\"origin\", ...) with a new url...")
+	err := EnsureRemoteIs("origin", "https://example.com/moved.git")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v := runGit(t, "config", "remote.origin.url"); v != "https://example.com/moved.git" {
+		t.Errorf("Expected the origin url to be updated. Got '%s'.", v)
+	}
+}
+
+func TestGetContextTimeout(t *testing.T) {
+	t.Log("Expecting GetContext and DoContext to stop a slow command when the context is done.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	slow := []string{"-c", "alias.slow=!sleep 10", "slow"}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// Run the function
+	t.Log("Running GetContext() on a slow command with a 100ms timeout...")
+	start := time.Now()
+	_, err := GetContext(ctx, slow...)
+
+	// Test the result
+	if err != context.DeadlineExceeded {
+		t.Errorf("Expected context.DeadlineExceeded. Got %v.", err)
+	}
+	if v := time.Since(start); v > 5*time.Second {
+		t.Errorf("Expected GetContext to return promptly. Got %s.", v)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	// Run the function
+	t.Log("Running DoContext() on a slow command cancelled after 100ms...")
+	start = time.Now()
+	code, err := DoContext(ctx, slow...)
+
+	// Test the result
+	if err != context.Canceled {
+		t.Errorf("Expected context.Canceled. Got %v (code %d).", err, code)
+	}
+	if v := time.Since(start); v > 5*time.Second {
+		t.Errorf("Expected DoContext to return promptly. Got %s.", v)
+	}
+
+	// Run the function
+	t.Log("Running DoContext() on a failing command...")
+	code, err = DoContext(context.Background(), "rev-parse", "--verify", "-q", "unknown")
+
+	// Test the result
+	if err != nil || code != 1 {
+		t.Errorf("Expected exit code 1 without error. Got %d, %v.", code, err)
+	}
+}
+
+func TestIsBare(t *testing.T) {
+	t.Log("Expecting IsBare to detect bare repositories.")
+	dir, done := initTestRepo(t)
+	defer done()
+
+	runGit(t, "init", "-q", "--bare", dir+"/bare.git")
+
+	// Run the function
+	t.Log("Running IsBare() on a repository with a working tree...")
+	bare, err := IsBare()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if bare {
+		t.Errorf("Expected the repository not to be bare. Got bare.")
+	}
+
+	// Run the function
+	t.Log("Running IsBare() on a bare repository...")
+	bare, err = NewRepo(dir + "/bare.git").IsBare()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if !bare {
+		t.Errorf("Expected the repository to be bare. Got not bare.")
+	}
+}
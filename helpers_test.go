@@ -0,0 +1,86 @@
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"testing"
+)
+
+// initTestRepo creates a temporary git repository and moves into it.
+// The returned function restores the previous directory and removes the repository.
+func initTestRepo(t *testing.T) (dir string, done func()) {
+	dir, err := ioutil.TempDir("", "go-git-test")
+	if err != nil {
+		t.Fatalf("Unable to create a temporary directory. %s", err)
+	}
+	curDir, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unable to get the current directory. %s", err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		t.Fatalf("Unable to move to '%s'. %s", dir, err)
+	}
+	SetLogFunc(func(string) {})
+
+	runGit(t, "init", "-q", "-b", "master")
+	runGit(t, "config", "user.name", "Test User")
+	runGit(t, "config", "user.email", "test@example.com")
+
+	done = func() {
+		os.Chdir(curDir)
+		os.RemoveAll(dir)
+		SetLogFunc(logOut)
+	}
+	return
+}
+
+// runGit runs a git command in the current directory and fails the test on error.
+func runGit(t *testing.T, opts ...string) string {
+	out, err := exec.Command("git", opts...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s failed. %s\n%s", strings.Join(opts, " "), err, out)
+	}
+	return strings.Trim(string(out), " \n")
+}
+
+// writeFile creates or replaces a file in the current directory.
+func writeFile(t *testing.T, file, content string) {
+	if dir := path.Dir(file); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Unable to create '%s'. %s", dir, err)
+		}
+	}
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatalf("Unable to write '%s'. %s", file, err)
+	}
+}
+
+// commitFile writes a file, stages it and commits it with the given message.
+func commitFile(t *testing.T, file, content, msg string) {
+	writeFile(t, file, content)
+	runGit(t, "add", file)
+	runGit(t, "commit", "-q", "-m", msg)
+}
+
+// runGitIn runs a git command in the given directory and fails the test on error.
+func runGitIn(t *testing.T, dir string, opts ...string) string {
+	return runGit(t, append([]string{"-C", dir}, opts...)...)
+}
+
+// initRemoteRepo creates a repository with one commit in dir, to be used as a remote.
+func initRemoteRepo(t *testing.T, dir string) {
+	runGit(t, "init", "-q", "-b", "master", dir)
+	runGitIn(t, dir, "config", "user.name", "Remote User")
+	runGitIn(t, dir, "config", "user.email", "remote@example.com")
+	commitFileIn(t, dir, "remote", "1", "remote first")
+}
+
+// commitFileIn writes a file in the given repository directory and commits it.
+func commitFileIn(t *testing.T, dir, file, content, msg string) {
+	writeFile(t, path.Join(dir, file), content)
+	runGitIn(t, dir, "add", file)
+	runGitIn(t, dir, "commit", "-q", "-m", msg)
+}
@@ -0,0 +1,384 @@
+package git
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// CommitInfo contains the metadata of a commit.
+// The author and committer identities are mapped by .mailmap.
+type CommitInfo struct {
+	Hash           string    `json:"hash"`
+	Author         string    `json:"author"`
+	AuthorEmail    string    `json:"author_email"`
+	AuthorDate     time.Time `json:"author_date"`
+	Committer      string    `json:"committer"`
+	CommitterEmail string    `json:"committer_email"`
+	CommitterDate  time.Time `json:"committer_date"`
+	Parents        []string  `json:"parents"`
+	Subject        string    `json:"subject"`
+	Body           string    `json:"body"`
+	// Encoding is the encoding the commit message was written with, if not UTF-8.
+	// The message is always converted to UTF-8.
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// commitFormat is the git log format parsed by logCommits.
+// Fields are separated by NUL and commits by a record separator.
+const commitFormat = "--format=%H%x00%aN%x00%aE%x00%aI%x00%cN%x00%cE%x00%cI%x00%P%x00%e%x00%s%x00%b%x1e"
+
+// CommitByHash returns the metadata of the commit identified by ref.
+func (r *Repo) CommitByHash(ref string) (CommitInfo, error) {
+	commits, err := r.logCommits("-1", ref, "--")
+	if err != nil {
+		return CommitInfo{}, err
+	}
+	if len(commits) == 0 {
+		return CommitInfo{}, fmt.Errorf("Unable to find the commit '%s'", ref)
+	}
+	return commits[0], nil
+}
+
+// CommitJSON returns the metadata of the commit identified by ref, encoded in JSON.
+// Dates are formatted as RFC3339.
+func (r *Repo) CommitJSON(ref string) ([]byte, error) {
+	commit, err := r.CommitByHash(ref)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(commit)
+}
+
+// CommitCount returns the number of commits reachable from ref.
+func (r *Repo) CommitCount(ref string) (int, error) {
+	v, err := r.Get("rev-list", "--count", ref, "--")
+	if err != nil {
+		return 0, fmt.Errorf("Unable to count the commits of '%s'. %s", ref, err)
+	}
+	return strconv.Atoi(v)
+}
+
+// PatchID returns the stable patch id of the commit identified by ref.
+// Two commits introducing the same change, like a commit and its cherry-pick, share the same patch id.
+func (r *Repo) PatchID(ref string) (string, error) {
+	patch, err := r.Get("show", "--format=", ref)
+	if err != nil {
+		return "", fmt.Errorf("Unable to show the commit '%s'. %s", ref, err)
+	}
+
+	v, err := r.getWithInput(patch+"\n", "patch-id", "--stable")
+	if err != nil {
+		return "", fmt.Errorf("Unable to compute the patch id of '%s'. %s", ref, err)
+	}
+	// <patch id> SP <commit id>
+	fields := strings.Fields(v)
+	if len(fields) == 0 {
+		return "", fmt.Errorf("Unable to compute the patch id of '%s'. The commit has no changes", ref)
+	}
+	return fields[0], nil
+}
+
+// RootCommit returns the first commit of the history of HEAD.
+// If the history has several roots, like after merging an unrelated history,
+// the root with the oldest committer date is returned.
+func (r *Repo) RootCommit() (string, error) {
+	// rev-list lists commits by date, newest first.
+	v, err := r.Get("rev-list", "--max-parents=0", "HEAD", "--")
+	if err != nil {
+		return "", fmt.Errorf("Unable to find the root commit. %s", err)
+	}
+	if v == "" {
+		return "", fmt.Errorf("Unable to find the root commit. No commit without parent found")
+	}
+	roots := strings.Split(v, "\n")
+	return roots[len(roots)-1], nil
+}
+
+// TimeSinceLastCommit returns the time elapsed since the HEAD commit date.
+// ErrNoCommits is returned if the repository has no commits.
+func (r *Repo) TimeSinceLastCommit() (time.Duration, error) {
+	if _, err := r.Get("rev-parse", "--verify", "-q", "HEAD"); err != nil {
+		return 0, ErrNoCommits
+	}
+	v, err := r.Get("log", "-1", "--format=%cI", "HEAD")
+	if err != nil {
+		return 0, fmt.Errorf("Unable to read the HEAD commit date. %s", err)
+	}
+	date, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return 0, err
+	}
+	return time.Since(date), nil
+}
+
+// UnpushedCommits returns the commits of the current branch which are not in its upstream, newest first.
+// ErrNoUpstream is returned if the current branch has no upstream.
+func (r *Repo) UnpushedCommits() ([]CommitInfo, error) {
+	if _, err := r.Get("rev-parse", "--verify", "-q", "@{upstream}"); err != nil {
+		return nil, ErrNoUpstream
+	}
+	return r.logCommits("@{upstream}..HEAD", "--")
+}
+
+// LogTopo returns the commits reachable from ref in topological order: a commit is always listed before
+// its parents, and commits of different lines of history are not intermixed, whatever their dates.
+// If max is greater than 0, at most max commits are returned.
+func (r *Repo) LogTopo(ref string, max int) ([]CommitInfo, error) {
+	opts := []string{"--topo-order"}
+	if max > 0 {
+		opts = append(opts, "-n", strconv.Itoa(max))
+	}
+	return r.logCommits(append(opts, ref, "--")...)
+}
+
+// RevListRange returns the hashes of the commits reachable from to but not from from, like git rev-list from..to.
+// Commits are listed newest first. If max is greater than 0, at most max commits are returned.
+// Unlike LogTopo, the commits metadata are not read.
+func (r *Repo) RevListRange(from, to string, max int) ([]string, error) {
+	opts := []string{"rev-list"}
+	if max > 0 {
+		opts = append(opts, "-n", strconv.Itoa(max))
+	}
+	v, err := r.Get(append(opts, from+".."+to, "--")...)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to list the commits of '%s..%s'. %s", from, to, err)
+	}
+	if v == "" {
+		return []string{}, nil
+	}
+	return strings.Split(v, "\n"), nil
+}
+
+// LogOptions selects the commits returned by Log.
+type LogOptions struct {
+	// From, if set, excludes the commits reachable from it, like git log From..To.
+	From string
+	// To is the commit the history is read from. HEAD if empty.
+	To string
+	// Paths, if set, limits the commits to the ones changing one of the paths.
+	Paths []string
+	// MaxCount, if greater than 0, is the maximum number of commits returned.
+	MaxCount int
+	// Author, if set, limits the commits to the ones which author name or email matches this regular expression.
+	Author string
+}
+
+// Log returns the commits selected by opts, newest first.
+func (r *Repo) Log(opts LogOptions) ([]CommitInfo, error) {
+	logOpts := make([]string, 0)
+	if opts.MaxCount > 0 {
+		logOpts = append(logOpts, "-n", strconv.Itoa(opts.MaxCount))
+	}
+	if opts.Author != "" {
+		logOpts = append(logOpts, "--author="+opts.Author)
+	}
+	to := opts.To
+	if to == "" {
+		to = "HEAD"
+	}
+	if opts.From != "" {
+		to = opts.From + ".." + to
+	}
+	logOpts = append(append(logOpts, to, "--"), opts.Paths...)
+
+	commits, err := r.logCommits(logOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read the history of '%s'. %s", to, err)
+	}
+	return commits, nil
+}
+
+// LastCommitOf returns the last commit of HEAD which changed path, a file or a directory.
+func (r *Repo) LastCommitOf(path string) (CommitInfo, error) {
+	commits, err := r.Log(LogOptions{Paths: []string{path}, MaxCount: 1})
+	if err != nil {
+		return CommitInfo{}, err
+	}
+	if len(commits) == 0 {
+		return CommitInfo{}, fmt.Errorf("Unable to find a commit changing '%s'", path)
+	}
+	return commits[0], nil
+}
+
+// CommitExists returns true if sha, or any commit name like a branch, identifies a commit of the repository.
+func (r *Repo) CommitExists(sha string) (bool, error) {
+	_, err := r.Get("rev-parse", "--verify", "-q", sha+"^{commit}")
+	switch exitCode(err) {
+	case 0:
+		return true, nil
+	case 1:
+		return false, nil
+	}
+	return false, fmt.Errorf("Unable to check if the commit '%s' exists. %s", sha, err)
+}
+
+// logCommits runs git log with the options given and parses the commits listed.
+func (r *Repo) logCommits(opts ...string) ([]CommitInfo, error) {
+	// git converts the messages from the commit encoding.
+	v, err := r.Get(append([]string{"log", "--encoding=UTF-8", commitFormat}, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make([]CommitInfo, 0)
+	for _, record := range strings.Split(v, "\x1e") {
+		record = strings.TrimLeft(record, "\n")
+		if record == "" {
+			continue
+		}
+		commit, err := parseCommit(record)
+		if err != nil {
+			return commits, err
+		}
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+// parseCommit decodes a commit record formatted with commitFormat.
+func parseCommit(record string) (commit CommitInfo, err error) {
+	fields := strings.SplitN(record, "\x00", 11)
+	if len(fields) != 11 {
+		return commit, fmt.Errorf("Unable to parse the commit record '%s'", record)
+	}
+
+	commit.Hash = fields[0]
+	for i := range fields {
+		fields[i] = latin1ToUTF8(fields[i])
+	}
+	commit.Author = fields[1]
+	commit.AuthorEmail = fields[2]
+	if commit.AuthorDate, err = time.Parse(time.RFC3339, fields[3]); err != nil {
+		return
+	}
+	commit.Committer = fields[4]
+	commit.CommitterEmail = fields[5]
+	if commit.CommitterDate, err = time.Parse(time.RFC3339, fields[6]); err != nil {
+		return
+	}
+	commit.Parents = strings.Fields(fields[7])
+	commit.Encoding = fields[8]
+	commit.Subject = fields[9]
+	commit.Body = strings.TrimRight(fields[10], "\n")
+	return
+}
+
+// latin1ToUTF8 decodes text as ISO-8859-1 if it is not valid UTF-8.
+// It is used for messages git could not convert, like a commit without encoding written in ISO-8859-1.
+func latin1ToUTF8(text string) string {
+	if utf8.ValidString(text) {
+		return text
+	}
+	runes := make([]rune, len(text))
+	for i := 0; i < len(text); i++ {
+		runes[i] = rune(text[i])
+	}
+	return string(runes)
+}
+
+// ErrNoCommits is returned when the repository has no commits yet.
+var ErrNoCommits = errors.New("The repository has no commits")
+
+// ErrNoUpstream is returned when the current branch has no upstream.
+var ErrNoUpstream = errors.New("The current branch has no upstream")
+
+// Contributor identifies an author and the number of commits authored.
+type Contributor struct {
+	Name    string
+	Email   string
+	Commits int
+}
+
+// FileContributors returns the authors of a file, the most active first.
+// The file history is followed across renames. Authors are identified as mapped by .mailmap.
+func (r *Repo) FileContributors(path string) ([]Contributor, error) {
+	v, err := r.Get("log", "--follow", "--format=%aN%x00%aE", "--", path)
+	if err != nil || v == "" {
+		return []Contributor{}, err
+	}
+	return tallyContributors(strings.Split(v, "\n")), nil
+}
+
+// CommitStats returns the number of commits of each author name, reachable from HEAD and committed
+// between since and until included. A zero time is not used as bound.
+// Authors are identified as mapped by .mailmap.
+func (r *Repo) CommitStats(since, until time.Time) (map[string]int, error) {
+	opts := []string{"shortlog", "-s", "-n"}
+	// RFC3339 dates keep the time zone, so git compares the same instants.
+	if !since.IsZero() {
+		opts = append(opts, "--since="+since.Format(time.RFC3339))
+	}
+	if !until.IsZero() {
+		opts = append(opts, "--until="+until.Format(time.RFC3339))
+	}
+	// Without a revision, shortlog reads a log from stdin.
+	v, err := r.Get(append(opts, "HEAD", "--")...)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to count the commits per author. %s", err)
+	}
+
+	stats := make(map[string]int)
+	for _, line := range strings.Split(v, "\n") {
+		// <count> TAB <author>
+		fields := strings.SplitN(strings.TrimSpace(line), "\t", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		count, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return stats, fmt.Errorf("Unable to parse the shortlog line '%s'. %s", line, err)
+		}
+		stats[fields[1]] = count
+	}
+	return stats, nil
+}
+
+// ResolveIdentity returns the canonical name and email of an identity, as mapped by .mailmap
+// or the mailmap.file configuration. An identity not mapped is returned as is.
+func (r *Repo) ResolveIdentity(name, email string) (string, string, error) {
+	contact := "<" + email + ">"
+	if name != "" {
+		contact = name + " " + contact
+	}
+	v, err := r.Get("check-mailmap", contact)
+	if err != nil {
+		return "", "", fmt.Errorf("Unable to resolve the identity '%s'. %s", contact, err)
+	}
+
+	re, _ := regexp.Compile(`^(.*?) ?<(.*)>$`)
+	fields := re.FindStringSubmatch(v)
+	if fields == nil {
+		return "", "", fmt.Errorf("Unable to parse the identity '%s'", v)
+	}
+	return fields[1], fields[2], nil
+}
+
+// tallyContributors counts the commits of each "<name>\x00<email>" line.
+func tallyContributors(lines []string) []Contributor {
+	index := make(map[string]int)
+	contributors := make([]Contributor, 0)
+	for _, line := range lines {
+		fields := strings.SplitN(line, "\x00", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		if i, found := index[line]; found {
+			contributors[i].Commits++
+			continue
+		}
+		index[line] = len(contributors)
+		contributors = append(contributors, Contributor{Name: fields[0], Email: fields[1], Commits: 1})
+	}
+
+	sort.SliceStable(contributors, func(i, j int) bool {
+		return contributors[i].Commits > contributors[j].Commits
+	})
+	return contributors
+}
@@ -0,0 +1,622 @@
+package git
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFileContributors(t *testing.T) {
+	t.Log("Expecting FileContributors to tally the authors of a file.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "old", "1", "first")
+	runGit(t, "mv", "old", "file")
+	runGit(t, "commit", "-q", "-m", "rename")
+	writeFile(t, "file", "2")
+	runGit(t, "commit", "-q", "-a", "-m", "second", "--author", "Other <other@example.com>")
+	commitFile(t, "unrelated", "1", "unrelated")
+
+	// Run the function
+	t.Log("Running CommitByHash(\"HEAD\")...")
+	commit, err := CommitByHash("HEAD")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if commit.Author != "Test User" || commit.AuthorEmail != "test@example.com" {
+		t.Errorf("Expected the author 'Test User <test@example.com>'. Got '%s <%s>'.", commit.Author, commit.AuthorEmail)
+	}
+
+	// Run the function
+	t.Log("Running FileContributors(\"file\")...")
+	contributors, err := FileContributors("file")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	expected := []Contributor{
+		{"Test User", "test@example.com", 2},
+		{"Other", "other@example.com", 1},
+	}
+	if v := len(contributors); v != len(expected) {
+		t.Fatalf("Expected %d contributors. Got %d.", len(expected), v)
+	}
+	for i, contributor := range contributors {
+		if contributor != expected[i] {
+			t.Errorf("Expected contributor %d to be %+v. Got %+v.", i, expected[i], contributor)
+		}
+	}
+}
+
+func TestCommitJSON(t *testing.T) {
+	t.Log("Expecting CommitJSON to export a commit metadata.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	os.Setenv("GIT_AUTHOR_DATE", "2017-05-04T10:20:30+02:00")
+	defer os.Unsetenv("GIT_AUTHOR_DATE")
+	writeFile(t, "file", "2")
+	runGit(t, "commit", "-q", "-a", "-m", "second\n\nThe body.")
+	parent := runGit(t, "rev-parse", "HEAD~1")
+
+	// Run the function
+	t.Log("Running CommitJSON(\"HEAD\")...")
+	data, err := CommitJSON("HEAD")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	var raw map[string]interface{}
+	if err = json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("Expected valid JSON. Got %s.", err)
+	}
+	if v := raw["author_date"]; v != "2017-05-04T10:20:30+02:00" {
+		t.Errorf("Expected author_date to be RFC3339 '2017-05-04T10:20:30+02:00'. Got '%v'.", v)
+	}
+
+	var commit CommitInfo
+	if err = json.Unmarshal(data, &commit); err != nil {
+		t.Fatalf("Expected JSON to decode as CommitInfo. Got %s.", err)
+	}
+	if v := runGit(t, "rev-parse", "HEAD"); commit.Hash != v {
+		t.Errorf("Expected hash '%s'. Got '%s'.", v, commit.Hash)
+	}
+	if commit.Author != "Test User" || commit.AuthorEmail != "test@example.com" {
+		t.Errorf("Expected author 'Test User <test@example.com>'. Got '%s <%s>'.", commit.Author, commit.AuthorEmail)
+	}
+	if commit.Committer != "Test User" {
+		t.Errorf("Expected committer 'Test User'. Got '%s'.", commit.Committer)
+	}
+	if commit.CommitterDate.IsZero() || time.Since(commit.CommitterDate) > time.Hour {
+		t.Errorf("Expected a recent committer date. Got %s.", commit.CommitterDate)
+	}
+	if len(commit.Parents) != 1 || commit.Parents[0] != parent {
+		t.Errorf("Expected parents [%s]. Got %v.", parent, commit.Parents)
+	}
+	if commit.Subject != "second" || commit.Body != "The body." {
+		t.Errorf("Expected subject 'second' and body 'The body.'. Got '%s' and '%s'.", commit.Subject, commit.Body)
+	}
+}
+
+func TestPatchID(t *testing.T) {
+	t.Log("Expecting PatchID to identify the same change in different commits.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1\n2\n3\n", "first")
+	runGit(t, "branch", "backport")
+	commitFile(t, "other", "1", "other")
+	commitFile(t, "file", "1\n2\nfixed\n", "fix")
+	runGit(t, "checkout", "-q", "backport")
+	runGit(t, "cherry-pick", "master")
+
+	// Run the function
+	t.Log("Running PatchID(\"master\") and PatchID(\"backport\")...")
+	original, err := PatchID("master")
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	picked, err := PatchID("backport")
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	other, err := PatchID("master~1")
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+
+	// Test the result
+	if original == "" || original != picked {
+		t.Errorf("Expected the cherry-pick to share the patch id '%s'. Got '%s'.", original, picked)
+	}
+	if other == original {
+		t.Errorf("Expected a different change to have a different patch id. Got '%s'.", other)
+	}
+}
+
+func TestTimeSinceLastCommit(t *testing.T) {
+	t.Log("Expecting TimeSinceLastCommit to return the HEAD commit age.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	// Run the function
+	t.Log("Running TimeSinceLastCommit() on an empty repo...")
+	if _, err := TimeSinceLastCommit(); err != ErrNoCommits {
+		t.Errorf("Expected ErrNoCommits. Got %v.", err)
+	}
+
+	os.Setenv("GIT_COMMITTER_DATE", time.Now().Add(-72*time.Hour).Format(time.RFC3339))
+	commitFile(t, "file", "1", "first")
+	os.Unsetenv("GIT_COMMITTER_DATE")
+
+	// Run the function
+	t.Log("Running TimeSinceLastCommit()...")
+	age, err := TimeSinceLastCommit()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if age < 72*time.Hour || age > 73*time.Hour {
+		t.Errorf("Expected about 72h. Got %s.", age)
+	}
+}
+
+func TestUnpushedCommits(t *testing.T) {
+	t.Log("Expecting UnpushedCommits to list local commits.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+
+	// Run the function
+	t.Log("Running UnpushedCommits() without upstream...")
+	if _, err := UnpushedCommits(); err != ErrNoUpstream {
+		t.Errorf("Expected ErrNoUpstream. Got %v.", err)
+	}
+
+	runGit(t, "remote", "add", "origin", "/nonexistent")
+	runGit(t, "update-ref", "refs/remotes/origin/master", "HEAD")
+	runGit(t, "branch", "--set-upstream-to", "origin/master")
+
+	// Run the function
+	t.Log("Running UnpushedCommits() when synced...")
+	if commits, err := UnpushedCommits(); err != nil {
+		t.Errorf("Expected no error. Got %s.", err)
+	} else if commits == nil || len(commits) != 0 {
+		t.Errorf("Expected an empty list. Got %v.", commits)
+	}
+
+	commitFile(t, "file", "2", "second")
+	commitFile(t, "file", "3", "third")
+
+	// Run the function
+	t.Log("Running UnpushedCommits()...")
+	commits, err := UnpushedCommits()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if len(commits) != 2 || commits[0].Subject != "third" || commits[1].Subject != "second" {
+		t.Errorf("Expected commits 'third' and 'second'. Got %+v.", commits)
+	}
+}
+
+func TestRootCommit(t *testing.T) {
+	t.Log("Expecting RootCommit to return the first commit.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	os.Setenv("GIT_COMMITTER_DATE", "2017-01-01T00:00:00Z")
+	commitFile(t, "file", "1", "first")
+	os.Unsetenv("GIT_COMMITTER_DATE")
+	root := runGit(t, "rev-parse", "HEAD")
+	commitFile(t, "file", "2", "second")
+
+	// Run the function
+	t.Log("Running RootCommit()...")
+	commit, err := RootCommit()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if commit != root {
+		t.Errorf("Expected %s. Got %s.", root, commit)
+	}
+
+	runGit(t, "checkout", "-q", "--orphan", "unrelated")
+	runGit(t, "rm", "-q", "-r", "-f", ".")
+	commitFile(t, "other", "1", "unrelated root")
+	runGit(t, "checkout", "-q", "master")
+	runGit(t, "merge", "-q", "--allow-unrelated-histories", "-m", "merge", "unrelated")
+
+	// Run the function
+	t.Log("Running RootCommit() with several roots...")
+	commit, err = RootCommit()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if commit != root {
+		t.Errorf("Expected the oldest root %s. Got %s.", root, commit)
+	}
+}
+
+func TestLogTopo(t *testing.T) {
+	t.Log("Expecting LogTopo to list commits in topological order whatever their dates.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitAt := func(file, date string) string {
+		os.Setenv("GIT_AUTHOR_DATE", date)
+		os.Setenv("GIT_COMMITTER_DATE", date)
+		defer os.Unsetenv("GIT_AUTHOR_DATE")
+		defer os.Unsetenv("GIT_COMMITTER_DATE")
+		commitFile(t, file, "1", file)
+		return runGit(t, "rev-parse", "HEAD")
+	}
+	commitAt("base", "2017-01-01T00:00:00Z")
+	runGit(t, "branch", "feature")
+	m1 := commitAt("m1", "2017-01-02T00:00:00Z")
+	m2 := commitAt("m2", "2017-01-04T00:00:00Z")
+	runGit(t, "checkout", "-q", "feature")
+	f1 := commitAt("f1", "2017-01-03T00:00:00Z")
+	f2 := commitAt("f2", "2017-01-05T00:00:00Z")
+	runGit(t, "checkout", "-q", "master")
+	runGit(t, "merge", "-q", "--no-edit", "feature")
+
+	// Run the function
+	t.Log("Running LogTopo(\"HEAD\", 0)...")
+	commits, err := LogTopo("HEAD", 0)
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v := len(commits); v != 6 {
+		t.Fatalf("Expected 6 commits. Got %d.", v)
+	}
+	index := make(map[string]int)
+	for i, commit := range commits {
+		index[commit.Hash] = i
+	}
+	for _, commit := range commits {
+		for _, parent := range commit.Parents {
+			if index[parent] < index[commit.Hash] {
+				t.Errorf("Expected %s to be listed after its child %s.", parent, commit.Hash)
+			}
+		}
+	}
+	if index[m1] != index[m2]+1 || index[f1] != index[f2]+1 {
+		t.Errorf("Expected the master and feature commits not to be intermixed. Got %v.", index)
+	}
+
+	// Run the function
+	t.Log("Running LogTopo(\"HEAD\", 2)...")
+	if commits, err = LogTopo("HEAD", 2); err != nil {
+		t.Errorf("Expected no error. Got %s.", err)
+	} else if v := len(commits); v != 2 {
+		t.Errorf("Expected 2 commits. Got %d.", v)
+	}
+}
+
+func TestCommitByHashEncoding(t *testing.T) {
+	t.Log("Expecting CommitByHash to convert non UTF-8 commit messages.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	// "café" in ISO-8859-1
+	writeFile(t, "message", "caf\xe9\n\nd\xe9j\xe0 vu\n")
+	writeFile(t, "file", "1")
+	runGit(t, "add", "file")
+	runGit(t, "-c", "i18n.commitEncoding=ISO-8859-1", "commit", "-q", "-F", "message")
+
+	// Run the function
+	t.Log("Running CommitByHash(\"HEAD\") on an ISO-8859-1 commit...")
+	commit, err := CommitByHash("HEAD")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if commit.Subject != "café" || commit.Body != "déjà vu" {
+		t.Errorf("Expected subject 'café' and body 'déjà vu'. Got %q and %q.", commit.Subject, commit.Body)
+	}
+	if commit.Encoding != "ISO-8859-1" {
+		t.Errorf("Expected encoding 'ISO-8859-1'. Got '%s'.", commit.Encoding)
+	}
+
+	writeFile(t, "file", "2")
+	runGit(t, "add", "file")
+	runGit(t, "commit", "-q", "-F", "message")
+
+	// Run the function
+	t.Log("Running CommitByHash(\"HEAD\") on an ISO-8859-1 commit without encoding...")
+	if commit, err = CommitByHash("HEAD"); err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if commit.Subject != "café" || commit.Encoding != "" {
+		t.Errorf("Expected subject 'café' without encoding. Got %q and '%s'.", commit.Subject, commit.Encoding)
+	}
+}
+
+func TestRevListRange(t *testing.T) {
+	t.Log("Expecting RevListRange to list the hashes of the commits of a range.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	runGit(t, "branch", "base")
+	commitFile(t, "file", "2", "second")
+	commitFile(t, "file", "3", "third")
+
+	tests := []struct {
+		from, to string
+		max      int
+		expected int
+	}{
+		{"base", "master", 0, 2},
+		{"base", "master", 1, 1},
+		{"master", "base", 0, 0},
+	}
+	for _, test := range tests {
+		// Run the function
+		hashes, err := RevListRange(test.from, test.to, test.max)
+
+		// Test the result
+		if err != nil {
+			t.Errorf("Expected no error. Got %s.", err)
+			continue
+		}
+		if len(hashes) != test.expected {
+			t.Errorf("Expected %d commits in %s..%s. Got %d.", test.expected, test.from, test.to, len(hashes))
+		}
+		if count, _ := CommitCount(test.from + ".." + test.to); test.max == 0 && count != len(hashes) {
+			t.Errorf("Expected the number of commits to be the commit count %d. Got %d.", count, len(hashes))
+		}
+	}
+	if hashes, _ := RevListRange("base", "master", 1); len(hashes) == 1 && hashes[0] != runGit(t, "rev-parse", "HEAD") {
+		t.Errorf("Expected the newest commit first. Got %s.", hashes[0])
+	}
+
+	// Run the function
+	t.Log("Running RevListRange(\"unknown\", \"master\", 0)...")
+	if _, err := RevListRange("unknown", "master", 0); err == nil {
+		t.Errorf("Expected an error. Got none.")
+	}
+}
+
+func TestCommitStats(t *testing.T) {
+	t.Log("Expecting CommitStats to count the commits per author in a date range.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitAt := func(file, author, date string) {
+		os.Setenv("GIT_AUTHOR_DATE", date)
+		os.Setenv("GIT_COMMITTER_DATE", date)
+		defer os.Unsetenv("GIT_AUTHOR_DATE")
+		defer os.Unsetenv("GIT_COMMITTER_DATE")
+		writeFile(t, file, date)
+		runGit(t, "add", file)
+		runGit(t, "commit", "-q", "-m", file, "--author", author)
+	}
+	commitAt("before", "Test User <test@example.com>", "2017-01-01T00:00:00Z")
+	commitAt("in1", "Test User <test@example.com>", "2017-01-02T00:00:00Z")
+	commitAt("in2", "Other <other@example.com>", "2017-01-03T00:00:00Z")
+	commitAt("in3", "Test User <test@example.com>", "2017-01-04T00:00:00Z")
+	// 2017-01-04T22:30:00Z, before the end of the range once the time zone is applied.
+	commitAt("in4", "Other <other@example.com>", "2017-01-05T00:30:00+02:00")
+	commitAt("after", "Other <other@example.com>", "2017-01-06T00:00:00Z")
+
+	since := time.Date(2017, 1, 2, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2017, 1, 5, 0, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	// Run the function
+	t.Logf("Running CommitStats(%s, %s)...", since, until)
+	stats, err := CommitStats(since, until)
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if expected := map[string]int{"Test User": 2, "Other": 2}; !reflect.DeepEqual(stats, expected) {
+		t.Errorf("Expected %v. Got %v.", expected, stats)
+	}
+
+	// Run the function
+	t.Log("Running CommitStats() without bounds...")
+	stats, err = CommitStats(time.Time{}, time.Time{})
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if expected := map[string]int{"Test User": 3, "Other": 3}; !reflect.DeepEqual(stats, expected) {
+		t.Errorf("Expected %v. Got %v.", expected, stats)
+	}
+}
+
+func TestResolveIdentity(t *testing.T) {
+	t.Log("Expecting .mailmap to collapse the identities of an author.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	writeFile(t, ".mailmap", "Test User <test@example.com> <old@example.com>\nTest User <test@example.com> Old Name <other@example.com>\n")
+	commitFile(t, "file", "1", "first")
+	writeFile(t, "file", "2")
+	runGit(t, "commit", "-q", "-a", "-m", "second", "--author", "Old Name <old@example.com>")
+	writeFile(t, "file", "3")
+	runGit(t, "commit", "-q", "-a", "-m", "third", "--author", "Old Name <other@example.com>")
+
+	tests := []struct {
+		name, email                 string
+		expectedName, expectedEmail string
+	}{
+		{"Old Name", "old@example.com", "Test User", "test@example.com"},
+		{"", "old@example.com", "Test User", "test@example.com"},
+		{"Old Name", "other@example.com", "Test User", "test@example.com"},
+		{"Someone", "someone@example.com", "Someone", "someone@example.com"},
+	}
+	for _, test := range tests {
+		// Run the function
+		name, email, err := ResolveIdentity(test.name, test.email)
+
+		// Test the result
+		if err != nil {
+			t.Errorf("Expected no error. Got %s.", err)
+		} else if name != test.expectedName || email != test.expectedEmail {
+			t.Errorf("Expected '%s <%s>' to resolve to '%s <%s>'. Got '%s <%s>'.", test.name, test.email, test.expectedName, test.expectedEmail, name, email)
+		}
+	}
+
+	// Run the function
+	t.Log("Running CommitByHash(\"HEAD\")...")
+	commit, err := CommitByHash("HEAD")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if commit.Author != "Test User" || commit.AuthorEmail != "test@example.com" {
+		t.Errorf("Expected the author 'Test User <test@example.com>'. Got '%s <%s>'.", commit.Author, commit.AuthorEmail)
+	}
+
+	// Run the function
+	t.Log("Running FileContributors(\"file\")...")
+	contributors, err := FileContributors("file")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if expected := []Contributor{{"Test User", "test@example.com", 3}}; !reflect.DeepEqual(contributors, expected) {
+		t.Errorf("Expected %+v. Got %+v.", expected, contributors)
+	}
+
+	// Run the function
+	t.Log("Running CommitStats() without bounds...")
+	stats, err := CommitStats(time.Time{}, time.Time{})
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if expected := map[string]int{"Test User": 3}; !reflect.DeepEqual(stats, expected) {
+		t.Errorf("Expected %v. Got %v.", expected, stats)
+	}
+}
+
+func TestLog(t *testing.T) {
+	t.Log("Expecting Log to filter the commits by range, path, count and author.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	runGit(t, "branch", "base")
+	commitFile(t, "other", "1", "second")
+	writeFile(t, "file", "2")
+	runGit(t, "add", "file")
+	runGit(t, "commit", "-q", "--author=Other Author <other@example.com>", "-m", "third")
+	commitFile(t, "other", "2", "fourth")
+
+	tests := []struct {
+		opts     LogOptions
+		expected []string
+	}{
+		{LogOptions{}, []string{"fourth", "third", "second", "first"}},
+		{LogOptions{From: "base"}, []string{"fourth", "third", "second"}},
+		{LogOptions{From: "base", To: "HEAD~1"}, []string{"third", "second"}},
+		{LogOptions{Paths: []string{"file"}}, []string{"third", "first"}},
+		{LogOptions{MaxCount: 2}, []string{"fourth", "third"}},
+		{LogOptions{Author: "other@example"}, []string{"third"}},
+		{LogOptions{From: "HEAD"}, []string{}},
+	}
+	for _, test := range tests {
+		// Run the function
+		commits, err := Log(test.opts)
+
+		// Test the result
+		if err != nil {
+			t.Errorf("Expected no error for %+v. Got %s.", test.opts, err)
+			continue
+		}
+		subjects := make([]string, 0)
+		for _, commit := range commits {
+			subjects = append(subjects, commit.Subject)
+		}
+		if !reflect.DeepEqual(subjects, test.expected) {
+			t.Errorf("Expected %q for %+v. Got %q.", test.expected, test.opts, subjects)
+		}
+	}
+
+	// Run the function
+	t.Log("Running Log() on an unknown ref...")
+	if _, err := Log(LogOptions{To: "unknown"}); err == nil {
+		t.Errorf("Expected an error. Got none.")
+	}
+}
+
+func TestLastCommitOf(t *testing.T) {
+	t.Log("Expecting LastCommitOf to return the last commit changing a path.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "dir/file", "1", "first")
+	commitFile(t, "other", "1", "second")
+
+	// Run the function
+	t.Log("Running LastCommitOf(\"dir\")...")
+	commit, err := LastCommitOf("dir")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if commit.Subject != "first" || commit.Hash != runGit(t, "rev-parse", "HEAD~1") {
+		t.Errorf("Expected the first commit. Got %+v.", commit)
+	}
+
+	// Run the function
+	t.Log("Running LastCommitOf(\"unknown\")...")
+	if _, err = LastCommitOf("unknown"); err == nil {
+		t.Errorf("Expected an error. Got none.")
+	}
+}
+
+func TestCommitExists(t *testing.T) {
+	t.Log("Expecting CommitExists to report if a commit exists.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	tests := map[string]bool{
+		runGit(t, "rev-parse", "HEAD"):            true,
+		runGit(t, "rev-parse", "--short", "HEAD"): true,
+		"master":                            true,
+		runGit(t, "rev-parse", "HEAD:file"): false,
+		ZeroID:                              false,
+		"unknown":                           false,
+	}
+	for sha, expected := range tests {
+		// Run the function
+		exists, err := CommitExists(sha)
+
+		// Test the result
+		if err != nil {
+			t.Errorf("Expected no error for '%s'. Got %s.", sha, err)
+		} else if exists != expected {
+			t.Errorf("Expected '%s' to exist %t. Got %t.", sha, expected, exists)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package git
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+)
+
+// LFSPatterns returns the patterns of files stored with git LFS, ie with the filter=lfs attribute
+// in the .gitattributes files. Patterns of a sub directory .gitattributes are prefixed by the directory.
+// git lfs is not required.
+func (r *Repo) LFSPatterns() ([]string, error) {
+	v, err := r.Get("ls-files", "-z", "--", ".gitattributes", "*/.gitattributes")
+	if err != nil {
+		return nil, fmt.Errorf("Unable to list the attributes files. %s", err)
+	}
+	files := []string{".gitattributes"}
+	for _, file := range strings.Split(v, "\x00") {
+		if file != "" && file != ".gitattributes" {
+			files = append(files, file)
+		}
+	}
+
+	patterns := make([]string, 0)
+	for _, file := range files {
+		content, err := ioutil.ReadFile(r.join(file))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return patterns, fmt.Errorf("Unable to read '%s'. %s", file, err)
+		}
+
+		dir := path.Dir(file)
+		for _, line := range strings.Split(string(content), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) < 2 || strings.HasPrefix(fields[0], "#") || !hasLFSFilter(fields[1:]) {
+				continue
+			}
+			pattern := fields[0]
+			if dir != "." {
+				pattern = path.Join(dir, pattern)
+			}
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns, nil
+}
+
+// IsLFSRepo returns true if some files are stored with git LFS.
+func (r *Repo) IsLFSRepo() (bool, error) {
+	patterns, err := r.LFSPatterns()
+	return len(patterns) > 0, err
+}
+
+// hasLFSFilter returns true if the attributes of a .gitattributes line set the lfs filter.
+func hasLFSFilter(attributes []string) bool {
+	for _, attribute := range attributes {
+		if attribute == "filter=lfs" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLFSPatterns(t *testing.T) {
+	t.Log("Expecting LFSPatterns to return the patterns with the lfs filter.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+
+	// Run the function
+	t.Log("Running IsLFSRepo() without LFS patterns...")
+	isLFS, err := IsLFSRepo()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if isLFS {
+		t.Errorf("Expected the repository not to use LFS. Got LFS.")
+	}
+
+	writeFile(t, ".gitattributes", "# Binaries\n*.psd filter=lfs diff=lfs merge=lfs -text\n*.txt text eol=lf\n")
+	writeFile(t, "assets/.gitattributes", "*.bin filter=lfs diff=lfs merge=lfs -text\n")
+	runGit(t, "add", ".gitattributes", "assets/.gitattributes")
+	runGit(t, "commit", "-q", "-m", "lfs")
+
+	// Run the function
+	t.Log("Running LFSPatterns()...")
+	patterns, err := LFSPatterns()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if expected := []string{"*.psd", "assets/*.bin"}; !reflect.DeepEqual(patterns, expected) {
+		t.Errorf("Expected %q. Got %q.", expected, patterns)
+	}
+	if isLFS, err = IsLFSRepo(); err != nil || !isLFS {
+		t.Errorf("Expected the repository to use LFS. Got %t, %v.", isLFS, err)
+	}
+}
@@ -0,0 +1,238 @@
+package git
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/forj-oss/forjj-modules/trace"
+)
+
+// PackInfo contains the size and object count of a GIT pack file.
+type PackInfo struct {
+	Path    string
+	Size    int64
+	Objects int
+}
+
+// PackStats returns the list of pack files of the repository with their size and object count.
+func (r *Repo) PackStats() ([]PackInfo, error) {
+	packDir, err := r.gitPath("objects/pack")
+	if err != nil {
+		return nil, fmt.Errorf("Unable to determine the pack directory. %s", err)
+	}
+
+	packs, err := filepath.Glob(filepath.Join(packDir, "*.pack"))
+	if err != nil {
+		return nil, err
+	}
+
+	countRE, _ := regexp.Compile(`: (\d+) objects?$`)
+	stats := make([]PackInfo, 0, len(packs))
+	for _, pack := range packs {
+		fi, err := os.Stat(pack)
+		if err != nil {
+			return stats, err
+		}
+		info := PackInfo{Path: pack, Size: fi.Size()}
+
+		v, err := r.Get("verify-pack", "-v", "--stat-only", pack)
+		if err != nil {
+			return stats, fmt.Errorf("Unable to verify the pack '%s'. %s", pack, err)
+		}
+		for _, line := range strings.Split(v, "\n") {
+			if m := countRE.FindStringSubmatch(line); m != nil {
+				count, _ := strconv.Atoi(m[1])
+				info.Objects += count
+			}
+		}
+		stats = append(stats, info)
+	}
+	return stats, nil
+}
+
+// RepairHead rewrites HEAD to point to defaultBranch when the HEAD file is missing or corrupted.
+// A valid HEAD is left untouched, even if it refers to an unborn branch, like in an empty repository
+// or after git checkout --orphan.
+func (r *Repo) RepairHead(defaultBranch string) error {
+	if _, err := r.Get("rev-parse", "--verify", "-q", "HEAD"); err == nil {
+		return nil
+	}
+
+	headFile, err := r.headPath()
+	if err != nil {
+		return fmt.Errorf("Unable to repair HEAD. %s", err)
+	}
+	headRef := "refs/heads/" + defaultBranch
+	content, err := ioutil.ReadFile(headFile)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err != nil || !isValidHead(string(content)) {
+		gotrace.Trace("HEAD is missing or corrupted. Setting it to %s", headRef)
+		return ioutil.WriteFile(headFile, []byte("ref: "+headRef+"\n"), 0644)
+	}
+
+	// HEAD is valid. A branch which does not exist is unborn, like after git checkout --orphan.
+	return nil
+}
+
+// headPath returns the path of the HEAD file of the repository.
+// git cannot find the GIT directory if HEAD is missing or corrupted. The .git directory, or the .git file
+// of a worktree or a submodule, is then used.
+func (r *Repo) headPath() (string, error) {
+	if v, err := r.gitPath("HEAD"); err == nil {
+		return v, nil
+	}
+
+	dotGit := r.join(".git")
+	fi, err := os.Stat(dotGit)
+	if err != nil {
+		return "", err
+	}
+	if fi.IsDir() {
+		return path.Join(dotGit, "HEAD"), nil
+	}
+	content, err := ioutil.ReadFile(dotGit)
+	if err != nil {
+		return "", err
+	}
+	gitDir := strings.TrimSpace(string(content))
+	if !strings.HasPrefix(gitDir, "gitdir: ") {
+		return "", fmt.Errorf("'%s' is not a GIT directory", dotGit)
+	}
+	return path.Join(r.join(strings.TrimPrefix(gitDir, "gitdir: ")), "HEAD"), nil
+}
+
+// isValidHead returns true if the HEAD content is a symbolic ref or a commit id, SHA-1 or SHA-256.
+func isValidHead(content string) bool {
+	headRE, _ := regexp.Compile(`^(ref: refs/\S+|[0-9a-f]{40}|[0-9a-f]{64})\n?$`)
+	return headRE.MatchString(content)
+}
+
+// GCIfNeeded runs git gc --auto, which packs the repository only if there are too many loose objects or packs.
+// It returns true if gc ran. gc is run in the foreground, even if gc.autoDetach is set.
+func (r *Repo) GCIfNeeded() (bool, error) {
+	before, err := r.countObjects()
+	if err != nil {
+		return false, err
+	}
+	if r.Do("-c", "gc.autoDetach=false", "gc", "--auto", "--quiet") > 0 {
+		return false, fmt.Errorf("Unable to run git gc")
+	}
+	after, err := r.countObjects()
+	if err != nil {
+		return false, err
+	}
+	return before != after, nil
+}
+
+// countObjects returns the number of loose objects and of packs, as reported by git count-objects.
+func (r *Repo) countObjects() (counts [2]int, err error) {
+	v, err := r.Get("count-objects", "-v")
+	if err != nil {
+		return counts, fmt.Errorf("Unable to count the objects. %s", err)
+	}
+	for _, line := range strings.Split(v, "\n") {
+		fields := strings.SplitN(line, ": ", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		switch fields[0] {
+		case "count":
+			counts[0], _ = strconv.Atoi(fields[1])
+		case "packs":
+			counts[1], _ = strconv.Atoi(fields[1])
+		}
+	}
+	return counts, nil
+}
+
+// UnreachableCommits returns the commits which cannot be reached from any ref, ignoring reflogs.
+// Those are commits lost by a reset or a rebase, until they are garbage collected.
+func (r *Repo) UnreachableCommits() ([]string, error) {
+	v, err := r.Get("fsck", "--unreachable", "--no-reflogs", "--no-progress")
+	if err != nil {
+		return nil, fmt.Errorf("Unable to check the repository. %s", err)
+	}
+
+	commits := make([]string, 0)
+	for _, line := range strings.Split(v, "\n") {
+		if fields := strings.Fields(line); len(fields) == 3 && fields[0] == "unreachable" && fields[1] == "commit" {
+			commits = append(commits, fields[2])
+		}
+	}
+	return commits, nil
+}
+
+// Levels of a FsckIssue.
+const (
+	FsckError   = "error"
+	FsckWarning = "warning"
+	FsckInfo    = "info"
+)
+
+// FsckIssue is an issue reported by git fsck.
+type FsckIssue struct {
+	// Level is FsckError for a corruption, FsckWarning, or FsckInfo for dangling objects.
+	Level string
+	// Kind is the issue reported, like "missing", "dangling", "broken link" or "error".
+	Kind       string
+	ObjectType string
+	Object     string
+	Message    string
+}
+
+// Fsck checks the repository integrity and returns the issues found.
+// With full, objects in packs are checked as well.
+// An error is returned only if the check could not be run, corruptions are reported as issues with FsckError level.
+func (r *Repo) Fsck(full bool) ([]FsckIssue, error) {
+	opts := []string{"fsck", "--no-progress"}
+	if full {
+		opts = append(opts, "--full")
+	}
+	out, err := r.combinedOutputCommand(r.command(opts...))
+	if exitCode(err) < 0 {
+		return nil, err
+	}
+
+	objectRE, _ := regexp.Compile(`^(dangling|missing|unreachable) (\w+) ([0-9a-f]+)$`)
+	linkRE, _ := regexp.Compile(`^broken link from +(\w+) ([0-9a-f]+)`)
+	prefixRE, _ := regexp.Compile(`^(error|warning|fatal|notice|bad)\b[^:]*: ?(.*)$`)
+
+	issues := make([]FsckIssue, 0)
+	hasError := false
+	for _, line := range strings.Split(strings.Trim(string(out), " \n"), "\n") {
+		if line == "" || strings.HasPrefix(line, "Checking ") {
+			continue
+		}
+		issue := FsckIssue{Level: FsckError, Kind: "error", Message: line}
+		if m := objectRE.FindStringSubmatch(line); m != nil {
+			issue.Kind, issue.ObjectType, issue.Object = m[1], m[2], m[3]
+			if issue.Kind != "missing" {
+				issue.Level = FsckInfo
+			}
+		} else if m := linkRE.FindStringSubmatch(line); m != nil {
+			issue.Kind, issue.ObjectType, issue.Object = "broken link", m[1], m[2]
+		} else if m := prefixRE.FindStringSubmatch(line); m != nil {
+			switch m[1] {
+			case "warning":
+				issue.Level, issue.Kind = FsckWarning, "warning"
+			case "notice":
+				issue.Level, issue.Kind = FsckInfo, "notice"
+			}
+		}
+		hasError = hasError || issue.Level == FsckError
+		issues = append(issues, issue)
+	}
+
+	if err != nil && !hasError {
+		return issues, fmt.Errorf("Unable to check the repository. %s", err)
+	}
+	return issues, nil
+}
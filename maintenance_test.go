@@ -0,0 +1,258 @@
+package git
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestPackStats(t *testing.T) {
+	t.Log("Expecting PackStats to report packs details.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	// Run the function
+	t.Log("Running PackStats() on a repo without packs...")
+	packs, err := PackStats()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v := len(packs); v != 0 {
+		t.Errorf("Expected no packs. Got %d.", v)
+	}
+
+	commitFile(t, "file", "1", "first")
+	commitFile(t, "file", "2", "second")
+	runGit(t, "gc", "-q")
+
+	// Run the function
+	t.Log("Running PackStats() after a gc...")
+	packs, err = PackStats()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v := len(packs); v != 1 {
+		t.Fatalf("Expected 1 pack. Got %d.", v)
+	}
+	// 2 commits, 2 trees and 2 blobs
+	if v := packs[0].Objects; v != 6 {
+		t.Errorf("Expected 6 objects in the pack. Got %d.", v)
+	}
+	if packs[0].Size == 0 {
+		t.Errorf("Expected a pack size. Got 0.")
+	}
+}
+
+func TestRepairHead(t *testing.T) {
+	t.Log("Expecting RepairHead to restore a missing or corrupted HEAD.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	sha := runGit(t, "rev-parse", "HEAD")
+
+	corruptions := map[string]func(){
+		"missing":   func() { os.Remove(".git/HEAD") },
+		"corrupted": func() { ioutil.WriteFile(".git/HEAD", []byte("garbage"), 0644) },
+	}
+
+	for name, corrupt := range corruptions {
+		corrupt()
+
+		// Run the function
+		t.Logf("Running RepairHead(\"master\") on a %s HEAD...", name)
+		err := RepairHead("master")
+
+		// Test the result
+		if err != nil {
+			t.Errorf("Expected no error on a %s HEAD. Got %s.", name, err)
+		} else if v := runGit(t, "rev-parse", "HEAD"); v != sha {
+			t.Errorf("Expected HEAD to be repaired to %s on a %s HEAD. Got %s.", sha, name, v)
+		}
+	}
+
+	runGit(t, "checkout", "-q", "--orphan", "fresh")
+	writeFile(t, "orphan", "1")
+	runGit(t, "add", "orphan")
+
+	// Run the function
+	t.Log("Running RepairHead(\"master\") on an orphan branch...")
+	err := RepairHead("master")
+
+	// Test the result
+	if err != nil {
+		t.Errorf("Expected no error on an orphan branch. Got %s.", err)
+	}
+	if v := runGit(t, "symbolic-ref", "HEAD"); v != "refs/heads/fresh" {
+		t.Errorf("Expected HEAD to stay on the orphan branch. Got %s.", v)
+	}
+	runGit(t, "checkout", "-q", "-f", "master")
+
+	worktreeDir, err := ioutil.TempDir("", "go-git-test")
+	if err != nil {
+		t.Fatalf("Unable to create a temporary directory. %s", err)
+	}
+	defer os.RemoveAll(worktreeDir)
+	worktreeDir = path.Join(worktreeDir, "worktree")
+	runGit(t, "worktree", "add", "-q", "-b", "feature", worktreeDir)
+	headFile := runGitIn(t, worktreeDir, "rev-parse", "--path-format=absolute", "--git-path", "HEAD")
+	ioutil.WriteFile(headFile, []byte("garbage"), 0644)
+
+	// Run the function
+	t.Log("Running RepairHead(\"feature\") on a corrupted worktree HEAD...")
+	err = NewRepo(worktreeDir).RepairHead("feature")
+
+	// Test the result
+	if err != nil {
+		t.Errorf("Expected no error on a worktree. Got %s.", err)
+	} else if v := runGitIn(t, worktreeDir, "symbolic-ref", "HEAD"); v != "refs/heads/feature" {
+		t.Errorf("Expected the worktree HEAD to be repaired to refs/heads/feature. Got %s.", v)
+	}
+	if v := runGit(t, "symbolic-ref", "HEAD"); v != "refs/heads/master" {
+		t.Errorf("Expected the main HEAD to be kept. Got %s.", v)
+	}
+}
+
+func TestIsValidHead(t *testing.T) {
+	t.Log("Expecting isValidHead to accept symbolic refs, SHA-1 and SHA-256 ids.")
+	tests := map[string]bool{
+		"ref: refs/heads/master\n":     true,
+		strings.Repeat("a", 40) + "\n": true,
+		strings.Repeat("a", 64) + "\n": true,
+		strings.Repeat("a", 50) + "\n": false,
+		"garbage":                      false,
+	}
+
+	for content, valid := range tests {
+		// Run the function
+		v := isValidHead(content)
+
+		// Test the result
+		if v != valid {
+			t.Errorf("Expected isValidHead(%q) to return %t. Got %t.", content, valid, v)
+		}
+	}
+}
+
+func TestUnreachableCommits(t *testing.T) {
+	t.Log("Expecting UnreachableCommits to list lost commits.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	commitFile(t, "file", "2", "second")
+	lost := runGit(t, "rev-parse", "HEAD")
+	runGit(t, "reset", "-q", "--hard", "HEAD~1")
+
+	// Run the function
+	t.Log("Running UnreachableCommits()...")
+	commits, err := UnreachableCommits()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if len(commits) != 1 || commits[0] != lost {
+		t.Errorf("Expected [%s]. Got %v.", lost, commits)
+	}
+}
+
+func TestFsck(t *testing.T) {
+	t.Log("Expecting Fsck to report repository issues.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	dangling := runGit(t, "hash-object", "-w", "--stdin")
+
+	// Run the function
+	t.Log("Running Fsck(true) on a healthy repo...")
+	issues, err := Fsck(true)
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	for _, issue := range issues {
+		if issue.Level == FsckError {
+			t.Errorf("Expected no error issue. Got %+v.", issue)
+		}
+	}
+	if len(issues) != 1 || issues[0].Kind != "dangling" || issues[0].Object != dangling {
+		t.Errorf("Expected the dangling blob %s to be reported. Got %+v.", dangling, issues)
+	}
+
+	blob := runGit(t, "rev-parse", "HEAD:file")
+	os.Remove(".git/objects/" + blob[:2] + "/" + blob[2:])
+
+	// Run the function
+	t.Log("Running Fsck(true) on a corrupted repo...")
+	issues, err = Fsck(true)
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	found := false
+	for _, issue := range issues {
+		if issue.Level == FsckError && issue.Kind == "missing" && issue.Object == blob {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected the missing blob %s to be reported. Got %+v.", blob, issues)
+	}
+}
+
+func TestGCIfNeeded(t *testing.T) {
+	t.Log("Expecting GCIfNeeded to run gc only when there are too many loose objects.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+
+	// Run the function
+	t.Log("Running GCIfNeeded() on a fresh repository...")
+	ran, err := GCIfNeeded()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if ran {
+		t.Errorf("Expected gc not to run. Got gc ran.")
+	}
+
+	// gc estimates the number of loose objects from the objects/17 directory.
+	runGit(t, "config", "gc.auto", "1")
+	for i, found := 0, 0; found < 3; i++ {
+		content := fmt.Sprintf("%d", i)
+		if sum := sha1.Sum([]byte(fmt.Sprintf("blob %d\x00%s", len(content), content))); sum[0] != 0x17 {
+			continue
+		}
+		writeFile(t, "blob", content)
+		runGit(t, "hash-object", "-w", "blob")
+		found++
+	}
+	os.Remove("blob")
+
+	// Run the function
+	t.Log("Running GCIfNeeded() with many loose objects...")
+	ran, err = GCIfNeeded()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if !ran {
+		t.Errorf("Expected gc to run. Got gc not run.")
+	}
+}
@@ -0,0 +1,225 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// ErrConflict is returned when an operation stopped on conflicts to be resolved.
+var ErrConflict = errors.New("Conflicts found. Resolve them and stage the result")
+
+// ErrMergeTreeUnsupported is returned by MergeTree when the installed git does not support the requested merge.
+// "git merge-tree --write-tree" requires git 2.38, and an explicit merge base requires git 2.40.
+var ErrMergeTreeUnsupported = errors.New("git merge-tree --write-tree is not supported by this git version")
+
+// MergeTreeResult is the result of a merge computed without working tree.
+type MergeTreeResult struct {
+	// Tree is the resulting tree. With conflicts, it contains files with conflict markers.
+	Tree      string
+	Clean     bool
+	Conflicts []string
+}
+
+// MergeMessagePreview returns the commit message git would generate when merging ref in the current branch.
+// The message is empty if ref is already merged.
+func (r *Repo) MergeMessagePreview(ref string) (string, error) {
+	sha, err := r.Get("rev-parse", "--verify", "-q", ref+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("Unable to find the commit '%s'. %s", ref, err)
+	}
+
+	fullName, _ := r.Get("rev-parse", "--symbolic-full-name", ref)
+	var desc string
+	switch {
+	case strings.HasPrefix(fullName, "refs/heads/"):
+		desc = "branch '" + strings.TrimPrefix(fullName, "refs/heads/") + "'"
+	case strings.HasPrefix(fullName, "refs/remotes/"):
+		desc = "remote-tracking branch '" + strings.TrimPrefix(fullName, "refs/remotes/") + "'"
+	case strings.HasPrefix(fullName, "refs/tags/"):
+		desc = "tag '" + strings.TrimPrefix(fullName, "refs/tags/") + "'"
+	default:
+		desc = "commit '" + ref + "'"
+	}
+
+	// Same format as FETCH_HEAD, as written by git merge.
+	return r.getWithInput(sha+"\t\t"+desc+" of .\n", "fmt-merge-msg")
+}
+
+// MergeTree merges theirs in ours without touching the index nor the working tree,
+// and returns the resulting tree.
+// If base is empty, git computes the merge base.
+func (r *Repo) MergeTree(base, ours, theirs string) (result MergeTreeResult, err error) {
+	opts := []string{"merge-tree", "--write-tree", "--name-only"}
+	if base != "" {
+		opts = append(opts, "--merge-base="+base)
+	}
+	opts = append(opts, ours, theirs)
+
+	v, err := r.Get(opts...)
+	switch exitCode(err) {
+	case 0:
+		result.Clean = true
+	case 1:
+		// Conflicts
+		err = nil
+	case 129:
+		return result, ErrMergeTreeUnsupported
+	default:
+		return result, fmt.Errorf("Unable to merge '%s' in '%s'. %s", theirs, ours, err)
+	}
+
+	// The tree id, conflicted files, then an empty line before messages.
+	lines := strings.Split(v, "\n")
+	result.Tree = lines[0]
+	result.Conflicts = make([]string, 0)
+	for _, line := range lines[1:] {
+		if line == "" {
+			break
+		}
+		result.Conflicts = append(result.Conflicts, line)
+	}
+	return
+}
+
+// WouldChangeAnything returns true if merging or rebasing ref would change the current tree.
+// It returns false if ref is already merged in HEAD or has the same content as HEAD.
+func (r *Repo) WouldChangeAnything(ref string) (bool, error) {
+	if merged, err := r.isAncestor(ref, "HEAD"); err != nil || merged {
+		return false, err
+	}
+
+	_, err := r.Get("diff", "--quiet", "HEAD", ref, "--")
+	switch exitCode(err) {
+	case 0:
+		return false, nil
+	case 1:
+		return true, nil
+	}
+	return false, fmt.Errorf("Unable to compare '%s' with HEAD. %s", ref, err)
+}
+
+// ApplyCommitChanges applies the changes of the commit ref to the index and the working tree, without committing.
+// ErrConflict is returned if the changes do not apply cleanly.
+func (r *Repo) ApplyCommitChanges(ref string) error {
+	if r.Do("cherry-pick", "--no-commit", ref) == 0 {
+		return nil
+	}
+	if paths, err := r.unmergedPaths(); err == nil && len(paths) > 0 {
+		return ErrConflict
+	}
+	return fmt.Errorf("Unable to apply the changes of '%s'", ref)
+}
+
+// RecordResolution records the resolution of the current conflicts, so ReplayResolution can apply it
+// to an identical conflict later, in this repository or any of its worktrees.
+// It must be called after the conflicted files are resolved in the working tree, and before they are staged.
+func (r *Repo) RecordResolution() error {
+	paths, err := r.unmergedPaths()
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("Unable to record a resolution. No unmerged files found")
+	}
+
+	resolved := make(map[string][]byte, len(paths))
+	for _, aPath := range paths {
+		content, err := ioutil.ReadFile(r.join(aPath))
+		if err != nil {
+			return fmt.Errorf("Unable to read the resolution of '%s'. %s", aPath, err)
+		}
+		if bytes.Contains(content, []byte("\n<<<<<<< ")) || bytes.HasPrefix(content, []byte("<<<<<<< ")) {
+			return fmt.Errorf("Unable to record a resolution. '%s' still contains conflict markers", aPath)
+		}
+		resolved[aPath] = content
+	}
+
+	// rerere records the conflict (preimage) from the conflict markers, then the resolution (postimage).
+	for _, aPath := range paths {
+		if _, err := r.Get("checkout", "-m", "--", aPath); err != nil {
+			return fmt.Errorf("Unable to recreate the conflict of '%s'. %s", aPath, err)
+		}
+	}
+	_, err = r.Get("-c", "rerere.enabled=true", "rerere")
+	for aPath, content := range resolved {
+		mode := os.FileMode(0644)
+		if fi, err := os.Stat(r.join(aPath)); err == nil {
+			mode = fi.Mode()
+		}
+		if err := ioutil.WriteFile(r.join(aPath), content, mode); err != nil {
+			return fmt.Errorf("Unable to restore the resolution of '%s'. %s", aPath, err)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("Unable to record the conflicts. %s", err)
+	}
+	if _, err = r.Get("-c", "rerere.enabled=true", "rerere"); err != nil {
+		return fmt.Errorf("Unable to record the resolution. %s", err)
+	}
+	return nil
+}
+
+// ReplayResolution applies the resolutions recorded by RecordResolution to the current conflicts.
+// It returns the files resolved. Those files are not staged.
+func (r *Repo) ReplayResolution() ([]string, error) {
+	paths, err := r.unmergedPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = r.Get("-c", "rerere.enabled=true", "rerere"); err != nil {
+		return nil, fmt.Errorf("Unable to replay the recorded resolutions. %s", err)
+	}
+	v, err := r.Get("-c", "rerere.enabled=true", "rerere", "remaining")
+	if err != nil {
+		return nil, fmt.Errorf("Unable to list the remaining conflicts. %s", err)
+	}
+	remaining := make(map[string]bool)
+	for _, aPath := range strings.Split(v, "\n") {
+		remaining[aPath] = true
+	}
+
+	resolved := make([]string, 0, len(paths))
+	for _, aPath := range paths {
+		if !remaining[aPath] {
+			resolved = append(resolved, aPath)
+		}
+	}
+	return resolved, nil
+}
+
+// HasUnmergedEntries returns true if the index has unmerged entries, ie conflicts not yet resolved
+// and staged after a merge, a rebase or a cherry-pick. A commit fails until they are resolved.
+// Conflict markers left in the working tree of a staged file are not detected.
+func (r *Repo) HasUnmergedEntries() (bool, error) {
+	paths, err := r.unmergedPaths()
+	if err != nil {
+		return false, err
+	}
+	return len(paths) > 0, nil
+}
+
+// unmergedPaths returns the list of files with unmerged entries in the index.
+func (r *Repo) unmergedPaths() ([]string, error) {
+	v, err := r.Get("ls-files", "-u", "-z")
+	if err != nil {
+		return nil, fmt.Errorf("Unable to list unmerged files. %s", err)
+	}
+
+	paths := make([]string, 0)
+	found := make(map[string]bool)
+	for _, entry := range strings.Split(v, "\x00") {
+		// <mode> SP <object> SP <stage> TAB <file>
+		fields := strings.SplitN(entry, "\t", 2)
+		if len(fields) != 2 || found[fields[1]] {
+			continue
+		}
+		found[fields[1]] = true
+		paths = append(paths, fields[1])
+	}
+	return paths, nil
+}
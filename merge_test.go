@@ -0,0 +1,295 @@
+package git
+
+import (
+	"io/ioutil"
+	"os/exec"
+	"testing"
+)
+
+func TestMergeMessagePreview(t *testing.T) {
+	t.Log("Expecting MergeMessagePreview to return the default merge message.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	runGit(t, "checkout", "-q", "-b", "feature")
+	commitFile(t, "other", "1", "second")
+	runGit(t, "checkout", "-q", "master")
+
+	// Run the function
+	t.Log("Running MergeMessagePreview(\"feature\")...")
+	msg, err := MergeMessagePreview("feature")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if msg != "Merge branch 'feature'" {
+		t.Errorf("Expected \"Merge branch 'feature'\". Got %q.", msg)
+	}
+
+	runGit(t, "checkout", "-q", "-b", "release")
+
+	// Run the function
+	t.Log("Running MergeMessagePreview(\"feature\") from the release branch...")
+	msg, err = MergeMessagePreview("feature")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if msg != "Merge branch 'feature' into release" {
+		t.Errorf("Expected \"Merge branch 'feature' into release\". Got %q.", msg)
+	}
+}
+
+func TestMergeTree(t *testing.T) {
+	t.Log("Expecting MergeTree to merge without working tree.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	runGit(t, "branch", "conflict")
+	runGit(t, "checkout", "-q", "-b", "clean")
+	commitFile(t, "other", "1", "other")
+	runGit(t, "checkout", "-q", "conflict")
+	commitFile(t, "file", "conflict", "conflict")
+	runGit(t, "checkout", "-q", "master")
+	commitFile(t, "file", "2", "second")
+	head := runGit(t, "rev-parse", "HEAD")
+
+	// Run the function
+	t.Log("Running MergeTree(\"\", \"master\", \"clean\")...")
+	result, err := MergeTree("", "master", "clean")
+
+	// Test the result
+	if err == ErrMergeTreeUnsupported {
+		t.Skip("git merge-tree --write-tree is not supported.")
+	}
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if !result.Clean || len(result.Conflicts) != 0 {
+		t.Errorf("Expected a clean merge. Got conflicts %v.", result.Conflicts)
+	}
+	if v := runGit(t, "ls-tree", "--name-only", result.Tree); v != "file\nother" {
+		t.Errorf("Expected the merged tree to contain 'file' and 'other'. Got '%s'.", v)
+	}
+
+	// Run the function
+	t.Log("Running MergeTree(\"\", \"master\", \"conflict\")...")
+	result, err = MergeTree("", "master", "conflict")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if result.Clean {
+		t.Errorf("Expected a conflicting merge. Got a clean one.")
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0] != "file" {
+		t.Errorf("Expected 'file' to be in conflict. Got %v.", result.Conflicts)
+	}
+	if result.Tree == "" {
+		t.Errorf("Expected a tree with conflicts. Got none.")
+	}
+	if v := runGit(t, "rev-parse", "HEAD"); v != head {
+		t.Errorf("Expected HEAD to be unchanged. Got %s.", v)
+	}
+
+	// Run the function
+	t.Log("Running MergeTree(\"master~1\", \"master\", \"clean\")...")
+	result, err = MergeTree("master~1", "master", "clean")
+
+	// Test the result
+	if err == ErrMergeTreeUnsupported {
+		t.Log("An explicit merge base is not supported by this git version.")
+	} else if err != nil {
+		t.Errorf("Expected no error. Got %s.", err)
+	} else if !result.Clean {
+		t.Errorf("Expected a clean merge. Got conflicts %v.", result.Conflicts)
+	}
+}
+
+func TestWouldChangeAnything(t *testing.T) {
+	t.Log("Expecting WouldChangeAnything to detect no-op merges.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	runGit(t, "branch", "same")
+	runGit(t, "branch", "behind")
+	runGit(t, "checkout", "-q", "-b", "reverted")
+	commitFile(t, "file", "2", "change")
+	commitFile(t, "file", "1", "revert")
+	runGit(t, "checkout", "-q", "-b", "diverged", "master")
+	commitFile(t, "other", "1", "other")
+	runGit(t, "checkout", "-q", "master")
+	commitFile(t, "file", "3", "second")
+	runGit(t, "reset", "-q", "--hard", "HEAD~1")
+
+	tests := map[string]bool{
+		"same":     false,
+		"behind":   false,
+		"reverted": false,
+		"diverged": true,
+	}
+	for ref, expected := range tests {
+		// Run the function
+		t.Logf("Running WouldChangeAnything(%q)...", ref)
+		changed, err := WouldChangeAnything(ref)
+
+		// Test the result
+		if err != nil {
+			t.Errorf("Expected no error. Got %s.", err)
+		} else if changed != expected {
+			t.Errorf("Expected '%s' to change the tree: %t. Got %t.", ref, expected, changed)
+		}
+	}
+}
+
+func TestRecordResolution(t *testing.T) {
+	t.Log("Expecting RecordResolution and ReplayResolution to share a conflict resolution.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "l1\nbase\nl3\n", "first")
+	runGit(t, "checkout", "-q", "-b", "feature")
+	commitFile(t, "file", "l1\nfeature\nl3\n", "feature")
+	runGit(t, "checkout", "-q", "master")
+	commitFile(t, "file", "l1\nmaster\nl3\n", "master")
+
+	if err := exec.Command("git", "merge", "-q", "feature").Run(); err == nil {
+		t.Fatalf("Expected the merge to conflict. Got no conflict.")
+	}
+	resolution := "l1\nresolved\nl3\n"
+	writeFile(t, "file", "l1\n<<<<<<< HEAD\nmaster\n=======\nfeature\n>>>>>>> feature\nl3\n")
+
+	// Run the function
+	t.Log("Running RecordResolution() with unresolved files...")
+	if err := RecordResolution(); err == nil {
+		t.Errorf("Expected an error. Got none.")
+	}
+
+	writeFile(t, "file", resolution)
+
+	// Run the function
+	t.Log("Running RecordResolution()...")
+	err := RecordResolution()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v, _ := ioutil.ReadFile("file"); string(v) != resolution {
+		t.Errorf("Expected the resolution to be kept in the working tree. Got '%s'.", v)
+	}
+
+	runGit(t, "merge", "--abort")
+	// Disable the automatic rerere to be sure ReplayResolution applies the resolution.
+	runGit(t, "config", "rerere.enabled", "false")
+	if err = exec.Command("git", "merge", "-q", "feature").Run(); err == nil {
+		t.Fatalf("Expected the merge to conflict. Got no conflict.")
+	}
+
+	// Run the function
+	t.Log("Running ReplayResolution()...")
+	resolved, err := ReplayResolution()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if len(resolved) != 1 || resolved[0] != "file" {
+		t.Errorf("Expected ['file'] to be resolved. Got %v.", resolved)
+	}
+	if v, _ := ioutil.ReadFile("file"); string(v) != resolution {
+		t.Errorf("Expected the recorded resolution to be applied. Got '%s'.", v)
+	}
+}
+
+func TestApplyCommitChanges(t *testing.T) {
+	t.Log("Expecting ApplyCommitChanges to stage a commit changes without committing.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	runGit(t, "checkout", "-q", "-b", "feature")
+	commitFile(t, "other", "feature", "feature")
+	commitFile(t, "file", "feature", "conflict")
+	runGit(t, "checkout", "-q", "master")
+	commitFile(t, "file", "master", "master")
+	head := runGit(t, "rev-parse", "HEAD")
+
+	// Run the function
+	t.Log("Running ApplyCommitChanges(\"feature~1\")...")
+	err := ApplyCommitChanges("feature~1")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v := runGit(t, "rev-parse", "HEAD"); v != head {
+		t.Errorf("Expected no commit to be created. Got HEAD at %s.", v)
+	}
+	if v := runGit(t, "status", "--porcelain"); v != "A  other" {
+		t.Errorf("Expected 'other' to be staged. Got '%s'.", v)
+	}
+
+	runGit(t, "reset", "-q", "--hard")
+
+	// Run the function
+	t.Log("Running ApplyCommitChanges(\"feature\")...")
+	if err = ApplyCommitChanges("feature"); err != ErrConflict {
+		t.Errorf("Expected ErrConflict. Got %v.", err)
+	}
+}
+
+func TestHasUnmergedEntries(t *testing.T) {
+	t.Log("Expecting HasUnmergedEntries to detect conflicts not staged yet.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "base\n", "first")
+	runGit(t, "checkout", "-q", "-b", "feature")
+	commitFile(t, "file", "feature\n", "feature")
+	runGit(t, "checkout", "-q", "master")
+	commitFile(t, "file", "master\n", "master")
+
+	// Run the function
+	t.Log("Running HasUnmergedEntries() on a clean repository...")
+	unmerged, err := HasUnmergedEntries()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if unmerged {
+		t.Errorf("Expected no unmerged entries. Got some.")
+	}
+
+	if err := exec.Command("git", "merge", "-q", "feature").Run(); err == nil {
+		t.Fatalf("Expected the merge to conflict. Got no conflict.")
+	}
+
+	// Run the function
+	t.Log("Running HasUnmergedEntries() in the middle of a conflict...")
+	unmerged, err = HasUnmergedEntries()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if !unmerged {
+		t.Errorf("Expected unmerged entries. Got none.")
+	}
+
+	writeFile(t, "file", "resolved\n")
+	runGit(t, "add", "file")
+
+	// Run the function
+	t.Log("Running HasUnmergedEntries() after staging the resolution...")
+	if unmerged, _ = HasUnmergedEntries(); unmerged {
+		t.Errorf("Expected no unmerged entries. Got some.")
+	}
+}
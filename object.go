@@ -0,0 +1,101 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ShowFileTo writes the content of a file at a given ref to w.
+// The content is streamed from git, so large files are never loaded in memory.
+func (r *Repo) ShowFileTo(ref, path string, w io.Writer) error {
+	opts := []string{"show", ref + ":" + path}
+	var stderr bytes.Buffer
+	cmd := r.command(opts...)
+	cmd.Stdout = w
+	cmd.Stderr = &stderr
+	if err := r.runCommand(cmd); err != nil {
+		return fmt.Errorf("Unable to show '%s' at '%s'. %s %s", path, ref, err, strings.Trim(stderr.String(), " \n"))
+	}
+	return nil
+}
+
+// ObjectType returns the type of the object identified by ref: blob, tree, commit or tag.
+// An annotated tag name returns "tag", while a lightweight tag name returns the type of its target.
+func (r *Repo) ObjectType(ref string) (string, error) {
+	v, err := r.Get("cat-file", "-t", ref)
+	if err != nil {
+		return "", fmt.Errorf("Unable to find the object '%s'. %s", ref, err)
+	}
+	return v, nil
+}
+
+// ErrObjectNotFound is returned when an object does not exist in the repository.
+var ErrObjectNotFound = errors.New("Object not found")
+
+// CatFile returns the content and the type of the object sha: blob, tree, commit or tag.
+// The content is returned as displayed by git cat-file -p: as is for a blob, a commit or a tag,
+// and as a list of entries for a tree. ErrObjectNotFound is returned if the object does not exist.
+func (r *Repo) CatFile(sha string) ([]byte, string, error) {
+	// git cat-file -e exits with 1 only if the object does not exist, and with 128 on other errors.
+	if _, err := r.Get("cat-file", "-e", sha); exitCode(err) == 1 {
+		return nil, "", ErrObjectNotFound
+	} else if err != nil {
+		return nil, "", fmt.Errorf("Unable to find the object '%s'. %s", sha, err)
+	}
+
+	objectType, err := r.Get("cat-file", "-t", sha)
+	if err != nil {
+		return nil, "", fmt.Errorf("Unable to find the object '%s'. %s", sha, err)
+	}
+
+	content, err := r.outputCommand(r.command("cat-file", "-p", sha))
+	if err != nil {
+		return nil, "", fmt.Errorf("Unable to read the object '%s'. %s", sha, err)
+	}
+	return content, objectType, nil
+}
+
+// TreeEntry is an entry of a git tree.
+type TreeEntry struct {
+	Mode string
+	Type string
+	Hash string
+	Path string
+}
+
+// ListTree returns the entries of the tree at ref.
+// If path is set, the content of this sub directory is listed.
+// With recursive, sub trees are expanded and only blobs and submodules are returned.
+func (r *Repo) ListTree(ref, path string, recursive bool) ([]TreeEntry, error) {
+	opts := []string{"ls-tree", "-z"}
+	if recursive {
+		opts = append(opts, "-r")
+	}
+	opts = append(opts, ref)
+	if path != "" {
+		opts = append(opts, strings.TrimSuffix(path, "/")+"/")
+	}
+
+	v, err := r.Get(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to list the tree '%s' at '%s'. %s", path, ref, err)
+	}
+
+	entries := make([]TreeEntry, 0)
+	for _, line := range strings.Split(v, "\x00") {
+		if line == "" {
+			continue
+		}
+		// <mode> SP <type> SP <object> TAB <file>
+		fields := strings.SplitN(line, "\t", 2)
+		info := strings.Fields(fields[0])
+		if len(fields) != 2 || len(info) != 3 {
+			return entries, fmt.Errorf("Unable to parse the tree entry '%s'", line)
+		}
+		entries = append(entries, TreeEntry{Mode: info[0], Type: info[1], Hash: info[2], Path: fields[1]})
+	}
+	return entries, nil
+}
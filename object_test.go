@@ -0,0 +1,168 @@
+package git
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"math/rand"
+	"testing"
+)
+
+func TestShowFileTo(t *testing.T) {
+	t.Log("Expecting ShowFileTo to stream a file content.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	data := make([]byte, 5*1024*1024)
+	rand.New(rand.NewSource(1)).Read(data)
+	commitFile(t, "big.bin", string(data), "big file")
+
+	// Run the function
+	t.Log("Running ShowFileTo(\"HEAD\", \"big.bin\", ...)...")
+	var out bytes.Buffer
+	err := ShowFileTo("HEAD", "big.bin", &out)
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if sha1.Sum(out.Bytes()) != sha1.Sum(data) {
+		t.Errorf("Expected the streamed content to match the committed file. Checksums differ.")
+	}
+
+	// Run the function
+	t.Log("Running ShowFileTo(\"HEAD\", \"missing\", ...)...")
+	if err = ShowFileTo("HEAD", "missing", &out); err == nil {
+		t.Errorf("Expected an error on a missing file. Got none.")
+	}
+}
+
+func TestListTree(t *testing.T) {
+	t.Log("Expecting ListTree to list a tree content at a ref.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	commitFile(t, "dir/a", "a", "second")
+	commitFile(t, "dir/sub/b", "b", "third")
+
+	tests := []struct {
+		path      string
+		recursive bool
+		expected  []string
+	}{
+		{"", false, []string{"tree dir", "blob file"}},
+		{"", true, []string{"blob dir/a", "blob dir/sub/b", "blob file"}},
+		{"dir", false, []string{"blob dir/a", "tree dir/sub"}},
+		{"dir/", true, []string{"blob dir/a", "blob dir/sub/b"}},
+	}
+
+	for _, test := range tests {
+		// Run the function
+		t.Logf("Running ListTree(\"HEAD\", %q, %t)...", test.path, test.recursive)
+		entries, err := ListTree("HEAD", test.path, test.recursive)
+
+		// Test the result
+		if err != nil {
+			t.Errorf("Expected no error. Got %s.", err)
+			continue
+		}
+		if v := len(entries); v != len(test.expected) {
+			t.Errorf("Expected %d entries. Got %d.", len(test.expected), v)
+			continue
+		}
+		for i, entry := range entries {
+			if v := entry.Type + " " + entry.Path; v != test.expected[i] {
+				t.Errorf("Expected entry %d to be '%s'. Got '%s'.", i, test.expected[i], v)
+			}
+			if len(entry.Hash) != 40 || entry.Mode == "" {
+				t.Errorf("Expected entry %d to have a mode and hash. Got '%s' and '%s'.", i, entry.Mode, entry.Hash)
+			}
+		}
+	}
+}
+
+func TestObjectType(t *testing.T) {
+	t.Log("Expecting ObjectType to return the type of an object.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	runGit(t, "tag", "-a", "-m", "annotated", "annotated")
+	runGit(t, "tag", "lightweight")
+
+	tests := map[string]string{
+		"HEAD:file":   "blob",
+		"HEAD^{tree}": "tree",
+		"HEAD":        "commit",
+		"annotated":   "tag",
+		"lightweight": "commit",
+	}
+	for ref, expected := range tests {
+		// Run the function
+		t.Logf("Running ObjectType(%q)...", ref)
+		objectType, err := ObjectType(ref)
+
+		// Test the result
+		if err != nil {
+			t.Errorf("Expected no error. Got %s.", err)
+		} else if objectType != expected {
+			t.Errorf("Expected '%s' to be a %s. Got %s.", ref, expected, objectType)
+		}
+	}
+
+	// Run the function
+	t.Log("Running ObjectType(\"unknown\")...")
+	if _, err := ObjectType("unknown"); err == nil {
+		t.Errorf("Expected an error. Got none.")
+	}
+}
+
+func TestCatFile(t *testing.T) {
+	t.Log("Expecting CatFile to return the content and type of objects.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "line1\nline2\n", "first")
+	runGit(t, "tag", "-a", "-m", "release", "v1")
+	blob := runGit(t, "rev-parse", "HEAD:file")
+	tree := runGit(t, "rev-parse", "HEAD^{tree}")
+	tag := runGit(t, "rev-parse", "v1")
+
+	tests := []struct {
+		sha          string
+		expectedType string
+		expected     string
+	}{
+		{blob, "blob", "line1\nline2\n"},
+		{tree, "tree", "100644 blob " + blob + "\tfile\n"},
+		{tag, "tag", "object " + runGit(t, "rev-parse", "HEAD") + "\ntype commit\ntag v1\n"},
+	}
+	for _, test := range tests {
+		// Run the function
+		content, objectType, err := CatFile(test.sha)
+
+		// Test the result
+		if err != nil {
+			t.Errorf("Expected no error. Got %s.", err)
+			continue
+		}
+		if objectType != test.expectedType {
+			t.Errorf("Expected %s to be a %s. Got %s.", test.sha, test.expectedType, objectType)
+		}
+		if !bytes.HasPrefix(content, []byte(test.expected)) {
+			t.Errorf("Expected the %s content to start with %q. Got %q.", test.expectedType, test.expected, content)
+		}
+	}
+
+	// Run the function
+	t.Log("Running CatFile() on a missing object...")
+	if _, _, err := CatFile(ZeroID); err != ErrObjectNotFound {
+		t.Errorf("Expected ErrObjectNotFound. Got %v.", err)
+	}
+
+	// Run the function
+	t.Log("Running CatFile() on an invalid object name...")
+	if _, _, err := CatFile("not-an-object"); err == nil || err == ErrObjectNotFound {
+		t.Errorf("Expected an error other than ErrObjectNotFound. Got %v.", err)
+	}
+}
@@ -0,0 +1,33 @@
+package git
+
+import (
+	"sync"
+	"time"
+)
+
+// commandObserver is called after every git command if set. See SetCommandObserver.
+var (
+	commandObserver     func(argv []string, duration time.Duration, exitCode int)
+	commandObserverLock sync.RWMutex
+)
+
+// SetCommandObserver sets a function called after every git command run by the package, to collect metrics.
+// It receives the command line, like ["git", "status", "--porcelain"], with credentials masked,
+// the time git took and its exit code, or -1 if git could not be run or was killed.
+// The observer can be called from several goroutines at once. Set it to nil to remove it.
+// It can be changed while commands are running.
+func SetCommandObserver(observer func(argv []string, duration time.Duration, exitCode int)) {
+	commandObserverLock.Lock()
+	defer commandObserverLock.Unlock()
+	commandObserver = observer
+}
+
+// observeCommand reports a git command started at start to the command observer.
+func observeCommand(argv []string, start time.Time, code int) {
+	commandObserverLock.RLock()
+	observer := commandObserver
+	commandObserverLock.RUnlock()
+	if observer != nil {
+		observer(redactCredentials(argv), time.Since(start), code)
+	}
+}
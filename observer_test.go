@@ -0,0 +1,82 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSetCommandObserver(t *testing.T) {
+	t.Log("Expecting the command observer to receive every git command run.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	type call struct {
+		argv     []string
+		duration time.Duration
+		exitCode int
+	}
+	calls := make([]call, 0)
+
+	// Run the function
+	SetCommandObserver(func(argv []string, duration time.Duration, exitCode int) {
+		calls = append(calls, call{argv, duration, exitCode})
+	})
+	defer SetCommandObserver(nil)
+	t.Log("Running Do(\"status\", \"-s\") and Get(\"rev-parse\", \"HEAD\")...")
+	Do("status", "-s")
+	Get("rev-parse", "--verify", "-q", "HEAD")
+
+	// Test the result
+	expected := []call{
+		{[]string{"git", "status", "-s"}, 0, 0},
+		{[]string{"git", "rev-parse", "--verify", "-q", "HEAD"}, 0, 1},
+	}
+	if v := len(calls); v != len(expected) {
+		t.Fatalf("Expected %d calls. Got %d.", len(expected), v)
+	}
+	for i, c := range calls {
+		if !reflect.DeepEqual(c.argv, expected[i].argv) {
+			t.Errorf("Expected the argv %q. Got %q.", expected[i].argv, c.argv)
+		}
+		if c.duration <= 0 {
+			t.Errorf("Expected a duration for %q. Got %s.", c.argv, c.duration)
+		}
+		if c.exitCode != expected[i].exitCode {
+			t.Errorf("Expected %q to exit with %d. Got %d.", c.argv, expected[i].exitCode, c.exitCode)
+		}
+	}
+
+	SetCommandObserver(nil)
+	calls = calls[:0]
+
+	// Run the function
+	t.Log("Running Get() without observer...")
+	Get("status")
+
+	// Test the result
+	if len(calls) != 0 {
+		t.Errorf("Expected no calls. Got %v.", calls)
+	}
+}
+
+func TestSetCommandObserverConcurrent(t *testing.T) {
+	t.Log("Expecting SetCommandObserver to be safe while commands are running. Run with -race.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	finished := make(chan bool)
+	go func() {
+		for i := 0; i < 10; i++ {
+			Get("status")
+		}
+		finished <- true
+	}()
+
+	// Run the function
+	for i := 0; i < 10; i++ {
+		SetCommandObserver(func([]string, time.Duration, int) {})
+		SetCommandObserver(nil)
+	}
+	<-finished
+}
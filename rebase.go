@@ -0,0 +1,112 @@
+package git
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+)
+
+// ErrNoRebaseInProgress is returned when no rebase is in progress.
+var ErrNoRebaseInProgress = errors.New("No rebase in progress")
+
+// RebaseInfo returns the details of the rebase in progress:
+// onto is the commit rebased onto, orig the commit HEAD was at before the rebase,
+// and head the name of the branch rebased, like "refs/heads/feature", or "detached HEAD".
+func (r *Repo) RebaseInfo() (onto, orig, head string, err error) {
+	for _, dir := range []string{"rebase-merge", "rebase-apply"} {
+		var stateDir string
+		if stateDir, err = r.gitPath(dir); err != nil {
+			return
+		}
+		if fi, statErr := os.Stat(stateDir); statErr != nil || !fi.IsDir() {
+			continue
+		}
+
+		if onto, err = readStateFile(stateDir, "onto"); err != nil {
+			return
+		}
+		if orig, err = readStateFile(stateDir, "orig-head"); err != nil {
+			return
+		}
+		head, err = readStateFile(stateDir, "head-name")
+		return
+	}
+	return "", "", "", ErrNoRebaseInProgress
+}
+
+// Operations in progress returned by ResumeState.
+const (
+	OperationNone       = ""
+	OperationMerge      = "merge"
+	OperationRebase     = "rebase"
+	OperationCherryPick = "cherry-pick"
+	OperationRevert     = "revert"
+)
+
+// ResumeInfo describes an operation left in progress, returned by ResumeState.
+type ResumeInfo struct {
+	// Operation is OperationMerge, OperationRebase, OperationCherryPick, OperationRevert,
+	// or OperationNone if no operation is in progress.
+	Operation string
+	// Conflicts lists the files with unresolved conflicts.
+	Conflicts []string
+	// Message is the commit message prepared by git, without the comment lines.
+	Message string
+}
+
+// ResumeState returns the operation in progress in the repository, like a merge interrupted by conflicts,
+// so a job restarting can decide to continue or abort it.
+func (r *Repo) ResumeState() (info ResumeInfo, err error) {
+	if info.Conflicts, err = r.unmergedPaths(); err != nil {
+		return
+	}
+
+	if _, _, _, rebaseErr := r.RebaseInfo(); rebaseErr == nil {
+		info.Operation = OperationRebase
+	} else if rebaseErr != ErrNoRebaseInProgress {
+		return info, rebaseErr
+	} else {
+		for _, state := range []struct{ file, operation string }{
+			{"MERGE_HEAD", OperationMerge},
+			{"CHERRY_PICK_HEAD", OperationCherryPick},
+			{"REVERT_HEAD", OperationRevert},
+		} {
+			if _, err := r.Get("rev-parse", "--verify", "-q", state.file); err == nil {
+				info.Operation = state.operation
+				break
+			}
+		}
+	}
+	if info.Operation == OperationNone {
+		return
+	}
+
+	// git prepares the message of the commit in progress in MERGE_MSG, also during a rebase.
+	file, err := r.gitPath("MERGE_MSG")
+	if err != nil {
+		return
+	}
+	content, readErr := ioutil.ReadFile(file)
+	if readErr != nil && !os.IsNotExist(readErr) {
+		return info, readErr
+	}
+	lines := make([]string, 0)
+	for _, line := range strings.Split(string(content), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	info.Message = strings.Trim(strings.Join(lines, "\n"), " \n")
+	return info, nil
+}
+
+// readStateFile reads a file of an operation state directory, like rebase-merge.
+func readStateFile(stateDir, name string) (string, error) {
+	content, err := ioutil.ReadFile(path.Join(stateDir, name))
+	if err != nil {
+		return "", err
+	}
+	return strings.Trim(string(content), " \n"), nil
+}
@@ -0,0 +1,118 @@
+package git
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestRebaseInfo(t *testing.T) {
+	t.Log("Expecting RebaseInfo to describe the rebase in progress.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	runGit(t, "checkout", "-q", "-b", "feature")
+	commitFile(t, "file", "feature", "feature")
+	orig := runGit(t, "rev-parse", "HEAD")
+	runGit(t, "checkout", "-q", "master")
+	commitFile(t, "file", "master", "master")
+	master := runGit(t, "rev-parse", "HEAD")
+	runGit(t, "checkout", "-q", "feature")
+
+	// Run the function
+	t.Log("Running RebaseInfo() without rebase...")
+	if _, _, _, err := RebaseInfo(); err != ErrNoRebaseInProgress {
+		t.Errorf("Expected ErrNoRebaseInProgress. Got %v.", err)
+	}
+
+	if err := exec.Command("git", "rebase", "master").Run(); err == nil {
+		t.Fatalf("Expected the rebase to conflict. Got no conflict.")
+	}
+
+	// Run the function
+	t.Log("Running RebaseInfo() during a rebase...")
+	onto, origHead, head, err := RebaseInfo()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if onto != master {
+		t.Errorf("Expected onto to be %s. Got %s.", master, onto)
+	}
+	if origHead != orig {
+		t.Errorf("Expected orig to be %s. Got %s.", orig, origHead)
+	}
+	if head != "refs/heads/feature" {
+		t.Errorf("Expected head to be 'refs/heads/feature'. Got '%s'.", head)
+	}
+}
+
+func TestResumeState(t *testing.T) {
+	t.Log("Expecting ResumeState to describe the operation left in progress.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	runGit(t, "checkout", "-q", "-b", "feature")
+	commitFile(t, "other", "feature", "other")
+	commitFile(t, "file", "feature", "feature change")
+	runGit(t, "checkout", "-q", "master")
+	commitFile(t, "file", "master", "master")
+	runGit(t, "checkout", "-q", "feature")
+
+	// Run the function
+	t.Log("Running ResumeState() without operation in progress...")
+	info, err := ResumeState()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if info.Operation != OperationNone || len(info.Conflicts) != 0 || info.Message != "" {
+		t.Errorf("Expected no operation in progress. Got %+v.", info)
+	}
+
+	if err := exec.Command("git", "rebase", "master").Run(); err == nil {
+		t.Fatalf("Expected the rebase to conflict. Got no conflict.")
+	}
+
+	// Run the function
+	t.Log("Running ResumeState() during a rebase...")
+	info, err = ResumeState()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if info.Operation != OperationRebase {
+		t.Errorf("Expected a rebase in progress. Got '%s'.", info.Operation)
+	}
+	if len(info.Conflicts) != 1 || info.Conflicts[0] != "file" {
+		t.Errorf("Expected ['file'] in conflict. Got %q.", info.Conflicts)
+	}
+	if info.Message != "feature change" {
+		t.Errorf("Expected the message 'feature change'. Got %q.", info.Message)
+	}
+
+	runGit(t, "rebase", "--abort")
+	runGit(t, "checkout", "-q", "master")
+	if err := exec.Command("git", "merge", "-q", "feature").Run(); err == nil {
+		t.Fatalf("Expected the merge to conflict. Got no conflict.")
+	}
+
+	// Run the function
+	t.Log("Running ResumeState() during a merge...")
+	info, err = ResumeState()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if info.Operation != OperationMerge || len(info.Conflicts) != 1 {
+		t.Errorf("Expected a merge in progress with 1 conflict. Got %+v.", info)
+	}
+	if info.Message != "Merge branch 'feature'" {
+		t.Errorf("Expected the message \"Merge branch 'feature'\". Got %q.", info.Message)
+	}
+}
@@ -0,0 +1,170 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ZeroID is the object id git uses for a missing object.
+const ZeroID = "0000000000000000000000000000000000000000"
+
+// Ref types returned by RefsPointingAt.
+const (
+	RefTypeBranch = "branch"
+	RefTypeRemote = "remote"
+	RefTypeTag    = "tag"
+	RefTypeOther  = "other"
+)
+
+// Ref is a named reference.
+type Ref struct {
+	// Name is the short name, like "master", "origin/master" or "v1.0".
+	Name     string
+	FullName string
+	Type     string
+}
+
+// RefUpdate describes a ref change applied by UpdateRefs.
+type RefUpdate struct {
+	Ref string
+	// NewValue is the new target of the ref. Empty to delete the ref.
+	NewValue string
+	// OldValue, if set, must be the current target of the ref for the update to be applied.
+	// ZeroID requires the ref not to exist yet.
+	OldValue string
+}
+
+// IsValidRefName returns true if name is a valid reference name, like a tag name or "refs/heads/master".
+// An error is returned only if git cannot be run.
+func IsValidRefName(name string) (bool, error) {
+	return checkRefFormat("--allow-onelevel", name)
+}
+
+// IsValidBranchName returns true if name can be used as a branch name.
+// An error is returned only if git cannot be run.
+func IsValidBranchName(name string) (bool, error) {
+	// git check-ref-format --branch expands names like "@{-1}", and git branch refuses names starting with "-".
+	if strings.HasPrefix(name, "-") || name == "HEAD" {
+		return false, nil
+	}
+	return checkRefFormat("refs/heads/" + name)
+}
+
+func checkRefFormat(opts ...string) (bool, error) {
+	_, err := Get(append([]string{"check-ref-format"}, opts...)...)
+	if err == nil {
+		return true, nil
+	}
+	if exitCode(err) > 0 {
+		return false, nil
+	}
+	return false, err
+}
+
+// UpdateRefs applies all ref updates in a single transaction: either all refs are updated or none.
+func (r *Repo) UpdateRefs(updates []RefUpdate) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	var input strings.Builder
+	input.WriteString("start\n")
+	for _, update := range updates {
+		if update.NewValue == "" {
+			fmt.Fprintf(&input, "delete %s", update.Ref)
+		} else {
+			fmt.Fprintf(&input, "update %s %s", update.Ref, update.NewValue)
+		}
+		// An empty old value would require the ref not to exist.
+		if update.OldValue != "" {
+			input.WriteString(" " + update.OldValue)
+		}
+		input.WriteString("\n")
+	}
+	input.WriteString("prepare\ncommit\n")
+
+	if _, err := r.getWithInput(input.String(), "update-ref", "--stdin"); err != nil {
+		return fmt.Errorf("Unable to update refs. None were updated. %s", err)
+	}
+	return nil
+}
+
+// RefsPointingAt returns the branches, remote-tracking branches and tags pointing at commit.
+// Annotated tags of commit are included.
+func (r *Repo) RefsPointingAt(commit string) ([]Ref, error) {
+	v, err := r.Get("for-each-ref", "--points-at="+commit, "--format=%(refname) %(refname:short)")
+	if err != nil {
+		return nil, fmt.Errorf("Unable to list refs pointing at '%s'. %s", commit, err)
+	}
+
+	refs := make([]Ref, 0)
+	for _, line := range strings.Split(v, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		ref := Ref{Name: fields[1], FullName: fields[0], Type: RefTypeOther}
+		switch {
+		case strings.HasPrefix(ref.FullName, "refs/heads/"):
+			ref.Type = RefTypeBranch
+		case strings.HasPrefix(ref.FullName, "refs/remotes/"):
+			ref.Type = RefTypeRemote
+		case strings.HasPrefix(ref.FullName, "refs/tags/"):
+			ref.Type = RefTypeTag
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+// ErrNoPreviousHead is returned when the reflog of HEAD has no previous entry.
+var ErrNoPreviousHead = errors.New("No previous HEAD in the reflog")
+
+// PreviousHead returns the commit HEAD pointed to before its last change, like a checkout, a reset or a commit.
+// It is read from the reflog as HEAD@{1}. ErrNoPreviousHead is returned if the reflog has no previous entry.
+func (r *Repo) PreviousHead() (string, error) {
+	v, err := r.Get("reflog", "show", "-n", "2", "--format=%H", "HEAD", "--")
+	if err != nil {
+		return "", fmt.Errorf("Unable to read the reflog of HEAD. %s", err)
+	}
+	entries := strings.Split(v, "\n")
+	if len(entries) < 2 {
+		return "", ErrNoPreviousHead
+	}
+	return entries[1], nil
+}
+
+// ErrNoReflog is returned when a ref has no reflog, for example if core.logAllRefUpdates is disabled.
+var ErrNoReflog = errors.New("No reflog found")
+
+// ReflogEntry is a previous value of a ref, recorded in its reflog.
+type ReflogEntry struct {
+	Hash string
+	// Selector identifies the entry, like "master@{1}".
+	Selector string
+	// Message describes the change, like "commit: first" or "reset: moving to HEAD~1".
+	Message string
+}
+
+// Reflog returns the entries of the reflog of ref, like a branch name or HEAD, the newest first.
+// The first entry is the current value of ref. ErrNoReflog is returned if ref has no reflog.
+func (r *Repo) Reflog(ref string) ([]ReflogEntry, error) {
+	v, err := r.Get("reflog", "show", "--format=%H%x00%gd%x00%gs", ref, "--")
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read the reflog of '%s'. %s", ref, err)
+	}
+	if v == "" {
+		return nil, ErrNoReflog
+	}
+
+	entries := make([]ReflogEntry, 0)
+	for _, line := range strings.Split(v, "\n") {
+		fields := strings.SplitN(line, "\x00", 3)
+		if len(fields) != 3 {
+			return entries, fmt.Errorf("Unable to parse the reflog entry '%s'", line)
+		}
+		entries = append(entries, ReflogEntry{Hash: fields[0], Selector: fields[1], Message: fields[2]})
+	}
+	return entries, nil
+}
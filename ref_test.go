@@ -0,0 +1,210 @@
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsValidRefName(t *testing.T) {
+	t.Log("Expecting IsValidRefName and IsValidBranchName to detect illegal names.")
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"master", true},
+		{"feature/my-branch", true},
+		{"v1.0.0", true},
+		{"foo..bar", false},
+		{"foo/", false},
+		{"foo.lock", false},
+		{"with space", false},
+		{"tilde~1", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		if v, err := IsValidRefName(test.name); err != nil {
+			t.Errorf("Expected no error for %q. Got %s.", test.name, err)
+		} else if v != test.valid {
+			t.Errorf("Expected IsValidRefName(%q) to return %t. Got %t.", test.name, test.valid, v)
+		}
+		if v, err := IsValidBranchName(test.name); err != nil {
+			t.Errorf("Expected no error for %q. Got %s.", test.name, err)
+		} else if v != test.valid {
+			t.Errorf("Expected IsValidBranchName(%q) to return %t. Got %t.", test.name, test.valid, v)
+		}
+	}
+
+	for _, name := range []string{"@{-1}", "-delete", "HEAD"} {
+		if v, err := IsValidBranchName(name); err != nil {
+			t.Errorf("Expected no error for %q. Got %s.", name, err)
+		} else if v {
+			t.Errorf("Expected IsValidBranchName(%q) to return false. Got true.", name)
+		}
+	}
+}
+
+func TestUpdateRefs(t *testing.T) {
+	t.Log("Expecting UpdateRefs to update refs atomically.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	first := runGit(t, "rev-parse", "HEAD")
+	commitFile(t, "file", "2", "second")
+	second := runGit(t, "rev-parse", "HEAD")
+	runGit(t, "branch", "update", first)
+	runGit(t, "branch", "delete", first)
+
+	// Run the function
+	t.Log("Running UpdateRefs() with a failing precondition...")
+	err := UpdateRefs([]RefUpdate{
+		{Ref: "refs/heads/update", NewValue: second, OldValue: first},
+		{Ref: "refs/heads/create", NewValue: second, OldValue: ZeroID},
+		{Ref: "refs/heads/delete", OldValue: second},
+	})
+
+	// Test the result
+	if err == nil {
+		t.Errorf("Expected an error. Got none.")
+	}
+	if v := runGit(t, "for-each-ref", "--format=%(refname:short) %(objectname)", "refs/heads"); v != "delete "+first+"\nmaster "+second+"\nupdate "+first {
+		t.Errorf("Expected no ref to be updated. Got '%s'.", v)
+	}
+
+	// Run the function
+	t.Log("Running UpdateRefs() with valid preconditions...")
+	err = UpdateRefs([]RefUpdate{
+		{Ref: "refs/heads/update", NewValue: second, OldValue: first},
+		{Ref: "refs/heads/create", NewValue: second, OldValue: ZeroID},
+		{Ref: "refs/heads/delete", OldValue: first},
+	})
+
+	// Test the result
+	if err != nil {
+		t.Errorf("Expected no error. Got %s.", err)
+	}
+	if v := runGit(t, "for-each-ref", "--format=%(refname:short) %(objectname)", "refs/heads"); v != "create "+second+"\nmaster "+second+"\nupdate "+second {
+		t.Errorf("Expected all refs to be updated. Got '%s'.", v)
+	}
+}
+
+func TestRefsPointingAt(t *testing.T) {
+	t.Log("Expecting RefsPointingAt to list branches and tags of a commit.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	commit := runGit(t, "rev-parse", "HEAD")
+	runGit(t, "branch", "release")
+	runGit(t, "tag", "v1.0")
+	runGit(t, "tag", "-a", "-m", "annotated", "v1.0-annotated")
+	runGit(t, "update-ref", "refs/remotes/origin/master", "HEAD")
+	commitFile(t, "file", "2", "second")
+	runGit(t, "tag", "v2.0")
+
+	// Run the function
+	t.Logf("Running RefsPointingAt(%q)...", commit)
+	refs, err := RefsPointingAt(commit)
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	expected := []Ref{
+		{"release", "refs/heads/release", RefTypeBranch},
+		{"origin/master", "refs/remotes/origin/master", RefTypeRemote},
+		{"v1.0", "refs/tags/v1.0", RefTypeTag},
+		{"v1.0-annotated", "refs/tags/v1.0-annotated", RefTypeTag},
+	}
+	if v := len(refs); v != len(expected) {
+		t.Fatalf("Expected %d refs. Got %d: %+v.", len(expected), v, refs)
+	}
+	for i, ref := range refs {
+		if ref != expected[i] {
+			t.Errorf("Expected ref %d to be %+v. Got %+v.", i, expected[i], ref)
+		}
+	}
+}
+
+func TestPreviousHead(t *testing.T) {
+	t.Log("Expecting PreviousHead to return the commit HEAD pointed to before the last change.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+
+	// Run the function
+	t.Log("Running PreviousHead() after the first commit...")
+	_, err := PreviousHead()
+
+	// Test the result
+	if err != ErrNoPreviousHead {
+		t.Errorf("Expected ErrNoPreviousHead. Got %v.", err)
+	}
+
+	first := runGit(t, "rev-parse", "HEAD")
+	commitFile(t, "file", "2", "second")
+	runGit(t, "checkout", "-q", "--detach", first)
+	second := runGit(t, "rev-parse", "master")
+
+	// Run the function
+	t.Log("Running PreviousHead() after a checkout...")
+	v, err := PreviousHead()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v != second {
+		t.Errorf("Expected the previous HEAD to be %s. Got %s.", second, v)
+	}
+}
+
+func TestReflog(t *testing.T) {
+	t.Log("Expecting Reflog to list the previous values of a branch.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	first := runGit(t, "rev-parse", "HEAD")
+	commitFile(t, "file", "2", "second")
+	second := runGit(t, "rev-parse", "HEAD")
+	runGit(t, "branch", "feature")
+	runGit(t, "branch", "-f", "feature", first)
+
+	// Run the function
+	t.Log("Running Reflog(\"feature\")...")
+	entries, err := Reflog("feature")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v := len(entries); v != 2 {
+		t.Fatalf("Expected 2 entries. Got %d.", v)
+	}
+	if entries[0].Hash != first || entries[0].Selector != "feature@{0}" {
+		t.Errorf("Expected the first entry to be feature@{0} at %s. Got %+v.", first, entries[0])
+	}
+	if entries[1].Hash != second || entries[1].Selector != "feature@{1}" || !strings.HasPrefix(entries[1].Message, "branch: Created") {
+		t.Errorf("Expected the second entry to be the branch creation at %s. Got %+v.", second, entries[1])
+	}
+
+	runGit(t, "-c", "core.logAllRefUpdates=false", "branch", "nolog")
+
+	// Run the function
+	t.Log("Running Reflog(\"nolog\") on a branch created without reflog...")
+	_, err = Reflog("nolog")
+
+	// Test the result
+	if err != ErrNoReflog {
+		t.Errorf("Expected ErrNoReflog. Got %v.", err)
+	}
+
+	// Run the function
+	t.Log("Running Reflog(\"unknown\")...")
+	if _, err = Reflog("unknown"); err == nil {
+		t.Errorf("Expected an error. Got none.")
+	}
+}
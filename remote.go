@@ -0,0 +1,318 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/forj-oss/forjj-modules/trace"
+)
+
+// ErrAuthenticationFailed is returned when a remote refused the git credentials.
+var ErrAuthenticationFailed = errors.New("Authentication failed")
+
+// ErrAlreadyCloned is returned by Clone when the destination is already a git repository.
+var ErrAlreadyCloned = errors.New("The destination is already a git repository")
+
+var authFailureRE = regexp.MustCompile(`(?i)(authentication failed|could not read (username|password)|terminal prompts disabled|permission denied \(publickey|access denied|returned error: 40[13])`)
+
+// RemoteReachable returns true if the remote repository at url answers within timeout.
+// An unreachable or unknown remote returns false without error, while a remote
+// refusing the credentials returns ErrAuthenticationFailed. git never prompts for credentials.
+// The credentials set by SetAuth or SetCredentialHelper are used.
+func (r *Repo) RemoteReachable(url string, timeout time.Duration) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := r.commandContext(ctx, r.withCredentials("ls-remote", "--exit-code", url)...)
+	addEnv(cmd, []string{"GIT_TERMINAL_PROMPT=0"})
+	cmd.Stderr = &stderr
+	err := r.runCommand(cmd)
+
+	switch {
+	case err == nil, exitCode(err) == 2:
+		// Exit code 2 means the remote was reached but has no refs.
+		return true, nil
+	case ctx.Err() != nil:
+		gotrace.Trace("Remote '%s' did not answer after %s", url, timeout)
+		return false, nil
+	case authFailureRE.Match(stderr.Bytes()):
+		return false, ErrAuthenticationFailed
+	case exitCode(err) < 0:
+		return false, err
+	}
+	gotrace.Trace("Remote '%s' is unreachable. %s", url, stderr.String())
+	return false, nil
+}
+
+// CloneOptions defines how a repository is cloned by Clone.
+type CloneOptions struct {
+	// Branch is checked out instead of the remote HEAD.
+	Branch string
+	// Depth, if not 0, creates a shallow clone with this number of commits.
+	Depth int
+	// SingleBranch clones only the history of Branch, or of the remote HEAD.
+	// Shallow clones are single branch unless NoSingleBranch is set.
+	SingleBranch bool
+	// NoSingleBranch clones all branches, even for a shallow clone.
+	NoSingleBranch bool
+}
+
+// Clone clones the repository at url in destPath and returns it.
+// The credentials and the runner of the current directory repository are used, and set in the Repo returned.
+// See SetAuth, SetCredentialHelper and SetRunner.
+// The destination and its parent directories are created if needed.
+// ErrAlreadyCloned is returned if destPath already contains a git repository.
+func Clone(url, destPath string, opts CloneOptions) (*Repo, error) {
+	if _, err := os.Stat(path.Join(destPath, ".git")); err == nil {
+		return nil, ErrAlreadyCloned
+	}
+
+	cloneOpts := []string{"clone"}
+	if opts.Branch != "" {
+		cloneOpts = append(cloneOpts, "--branch", opts.Branch)
+	}
+	if opts.Depth > 0 {
+		cloneOpts = append(cloneOpts, "--depth", strconv.Itoa(opts.Depth))
+	}
+	if opts.SingleBranch {
+		cloneOpts = append(cloneOpts, "--single-branch")
+	} else if opts.NoSingleBranch {
+		cloneOpts = append(cloneOpts, "--no-single-branch")
+	}
+	cloneOpts = append(cloneOpts, "--", url, destPath)
+
+	if err := defaultRepo.doError(defaultRepo.withCredentials(cloneOpts...)...); err != nil {
+		return nil, fmt.Errorf("Unable to clone '%s' in '%s'. %w", url, destPath, err)
+	}
+	return defaultRepo.repoAt(destPath), nil
+}
+
+// FetchOptions defines how Fetch downloads the remote changes.
+type FetchOptions struct {
+	// Prune removes the remote-tracking branches which do not exist on the remote anymore.
+	Prune bool
+	// Tags downloads all tags of the remote, not only the tags pointing at the fetched commits.
+	Tags bool
+}
+
+// Fetch downloads the branches and tags of remote and updates its remote-tracking branches.
+// If remote is empty, the default remote is used. See Repo.DefaultRemote.
+func (r *Repo) Fetch(remote string, opts FetchOptions) error {
+	remote, err := r.resolveRemote(remote)
+	if err != nil {
+		return err
+	}
+	fetchOpts := []string{"fetch"}
+	if opts.Prune {
+		fetchOpts = append(fetchOpts, "--prune")
+	}
+	if opts.Tags {
+		fetchOpts = append(fetchOpts, "--tags")
+	}
+	fetchOpts = append(fetchOpts, remote)
+
+	if err := r.doError(r.withCredentials(fetchOpts...)...); err != nil {
+		return fmt.Errorf("Unable to fetch '%s'. %w", remote, err)
+	}
+	return nil
+}
+
+// PullOptions defines how Pull integrates the remote changes in the current branch.
+// Without option, the git configuration applies, as reported by PullStrategy: the changes are merged
+// if none of pull.rebase, pull.ff and branch.<branch>.rebase is set.
+type PullOptions struct {
+	// Rebase rebases the local commits on the remote branch instead of merging it.
+	Rebase bool
+	// FastForwardOnly fails if the current branch has diverged from the remote branch.
+	FastForwardOnly bool
+}
+
+// Pull fetches branch from remote and integrates it in the current branch.
+// If remote is empty, the default remote is used. See Repo.DefaultRemote. If branch is empty,
+// the upstream branch of the current branch is pulled.
+// If the pull stops on conflicts, IsMergeConflict returns true for the error returned.
+// They must be resolved, or the merge or rebase aborted. See ResumeState.
+func (r *Repo) Pull(remote, branch string, opts PullOptions) error {
+	pullOpts := []string{"pull"}
+	if opts.Rebase {
+		pullOpts = append(pullOpts, "--rebase")
+	}
+	if opts.FastForwardOnly {
+		pullOpts = append(pullOpts, "--ff-only")
+	}
+	if !opts.Rebase && !opts.FastForwardOnly && !r.pullConfigured() {
+		// git refuses to pull diverged branches if the pull mode is not configured.
+		pullOpts = append(pullOpts, "--no-rebase")
+	}
+	if remote != "" || branch != "" {
+		remote, err := r.resolveRemote(remote)
+		if err != nil {
+			return err
+		}
+		pullOpts = append(pullOpts, remote)
+		if branch != "" {
+			pullOpts = append(pullOpts, branch)
+		}
+	}
+
+	if err := r.doError(r.withCredentials(pullOpts...)...); err != nil {
+		return fmt.Errorf("Unable to pull. %w", err)
+	}
+	return nil
+}
+
+// pullConfigured returns true if pull.rebase, pull.ff or branch.<branch>.rebase of the current branch is set.
+func (r *Repo) pullConfigured() bool {
+	keys := []string{"pull.rebase", "pull.ff"}
+	if branch, _ := r.Get("symbolic-ref", "--short", "-q", "HEAD"); branch != "" {
+		keys = append(keys, "branch."+branch+".rebase")
+	}
+	for _, key := range keys {
+		if _, found, _ := r.ConfigGet(key); found {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoteHasNewCommits returns true if the remote branch differs from the local remote-tracking branch,
+// ie a fetch would bring new commits.
+// It returns false if the branch does not exist on the remote.
+// If remote is empty, the default remote is used. See Repo.DefaultRemote.
+func (r *Repo) RemoteHasNewCommits(remote, branch string) (bool, error) {
+	remote, err := r.resolveRemote(remote)
+	if err != nil {
+		return false, err
+	}
+	v, err := r.Get(r.withCredentials("ls-remote", remote, "refs/heads/"+branch)...)
+	if err != nil {
+		return false, fmt.Errorf("Unable to query the remote '%s'. %s", remote, err)
+	}
+	fields := strings.Fields(v)
+	if len(fields) == 0 {
+		return false, nil
+	}
+
+	local, _ := r.Get("rev-parse", "--verify", "-q", "refs/remotes/"+remote+"/"+branch)
+	return fields[0] != local, nil
+}
+
+// RefIsNew returns true if branch exists on the remote but has no remote-tracking branch yet,
+// ie the next fetch would create it.
+// If remote is empty, the default remote is used. See Repo.DefaultRemote.
+func (r *Repo) RefIsNew(remote, branch string) (bool, error) {
+	remote, err := r.resolveRemote(remote)
+	if err != nil {
+		return false, err
+	}
+	if _, err := r.Get("rev-parse", "--verify", "-q", "refs/remotes/"+remote+"/"+branch); err == nil {
+		return false, nil
+	}
+	v, err := r.Get(r.withCredentials("ls-remote", remote, "refs/heads/"+branch)...)
+	if err != nil {
+		return false, fmt.Errorf("Unable to query the remote '%s'. %s", remote, err)
+	}
+	return v != "", nil
+}
+
+// PushCreatesBranch returns true if branch does not exist on the remote yet, ie pushing it would create it.
+// The remote is queried, the remote-tracking branches are not used.
+// If remote is empty, the default remote is used. See Repo.DefaultRemote.
+func (r *Repo) PushCreatesBranch(remote, branch string) (bool, error) {
+	remote, err := r.resolveRemote(remote)
+	if err != nil {
+		return false, err
+	}
+	v, err := r.Get(r.withCredentials("ls-remote", remote, "refs/heads/"+branch)...)
+	if err != nil {
+		return false, fmt.Errorf("Unable to query the remote '%s'. %s", remote, err)
+	}
+	return v == "", nil
+}
+
+// resolveRemote returns remote, or the default remote of the repository if remote is empty:
+// DefaultRemote, the checkout.defaultRemote configuration, or the only remote defined.
+func (r *Repo) resolveRemote(remote string) (string, error) {
+	if remote != "" {
+		return remote, nil
+	}
+	if r.DefaultRemote != "" {
+		return r.DefaultRemote, nil
+	}
+	if v, found, err := r.ConfigGet("checkout.defaultRemote"); err != nil {
+		return "", err
+	} else if found && v != "" {
+		return v, nil
+	}
+
+	remotes, err := r.Remotes()
+	if err != nil {
+		return "", err
+	}
+	if len(remotes) != 1 {
+		return "", fmt.Errorf("Unable to determine the default remote. %d remotes are defined. Set checkout.defaultRemote or give a remote", len(remotes))
+	}
+	return remotes[0], nil
+}
+
+// Remotes returns the list of remotes defined.
+func (r *Repo) Remotes() ([]string, error) {
+	v, err := r.Get("remote")
+	if err != nil || v == "" {
+		return []string{}, err
+	}
+	return strings.Split(v, "\n"), nil
+}
+
+// PushableRemotes returns the remotes which are expected to accept a push.
+//
+// This is a best-effort guess based on the push url of each remote:
+// - an empty url or a git:// url is read-only.
+// - an http(s) url without user information is read-only, unless credentials are configured: a credential helper or a Repo token.
+// - any other url (ssh, scp-like, file or local path) is writable.
+func (r *Repo) PushableRemotes() ([]string, error) {
+	remotes, err := r.Remotes()
+	if err != nil {
+		return nil, err
+	}
+
+	hasCredentials := r.credentialHelper != "" || (r.auth != nil && r.auth.Token != "")
+	if !hasCredentials {
+		helper, _ := r.Get("config", "--get", "credential.helper")
+		hasCredentials = helper != ""
+	}
+	pushable := make([]string, 0, len(remotes))
+	for _, remote := range remotes {
+		url, err := r.Get("remote", "get-url", "--push", remote)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to get the push url of '%s'. %s", remote, err)
+		}
+		if isPushableURL(url, hasCredentials) {
+			pushable = append(pushable, remote)
+		}
+	}
+	return pushable, nil
+}
+
+func isPushableURL(url string, hasCredentials bool) bool {
+	switch {
+	case url == "", strings.HasPrefix(url, "git://"):
+		return false
+	case strings.HasPrefix(url, "https://"), strings.HasPrefix(url, "http://"):
+		host := url[strings.Index(url, "://")+3:]
+		if i := strings.Index(host, "/"); i >= 0 {
+			host = host[:i]
+		}
+		return strings.Contains(host, "@") || hasCredentials
+	}
+	return true
+}
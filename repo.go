@@ -0,0 +1,103 @@
+package git
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/forj-oss/forjj-modules/trace"
+)
+
+// Repo is a GIT repository identified by its directory.
+// Every git command is run in this directory, set as the command directory, so several repositories
+// can be used without changing the current directory, including from several goroutines.
+// DefaultRemote, SetCredentialHelper, SetAuth and SetRunner must be set before the Repo is shared between goroutines.
+type Repo struct {
+	// DefaultRemote is the remote used by commands like Fetch when no remote is given.
+	// If empty, the checkout.defaultRemote configuration or the only remote defined is used.
+	DefaultRemote string
+
+	path             string
+	credentialHelper string
+	auth             *AuthConfig
+	runner           Runner
+	statusCache      statusCache
+}
+
+// killWaitDelay is the time given to a killed git command to release its output.
+const killWaitDelay = time.Second
+
+// defaultRepo is the repository of the current directory, used by the package functions.
+var defaultRepo = NewRepo(".")
+
+// NewRepo returns a Repo to run git commands in the directory aPath.
+// The repository is not checked nor created. See EnsureRepoExist.
+func NewRepo(aPath string) *Repo {
+	if aPath == "" {
+		aPath = "."
+	}
+	return &Repo{path: aPath}
+}
+
+// repoAt returns a Repo for the directory aPath, using the credentials and the runner of r.
+func (r *Repo) repoAt(aPath string) *Repo {
+	repo := NewRepo(aPath)
+	repo.credentialHelper, repo.auth, repo.runner = r.credentialHelper, r.auth, r.runner
+	return repo
+}
+
+// Path returns the directory of the repository.
+func (r *Repo) Path() string {
+	return r.path
+}
+
+// args returns the git command options as displayed in logs, with "-C <path>" set before the git command
+// to show the repository directory. It is omitted for the current directory.
+// git is run in the directory with the same result. See commandContext.
+func (r *Repo) args(opts []string) []string {
+	if r.path == "." {
+		return opts
+	}
+	return append([]string{"-C", r.path}, opts...)
+}
+
+// command returns the git command to run in the repository directory.
+func (r *Repo) command(opts ...string) *exec.Cmd {
+	return r.commandContext(context.Background(), opts...)
+}
+
+// commandContext returns the git command to run in the repository directory, killed when ctx is done.
+func (r *Repo) commandContext(ctx context.Context, opts ...string) *exec.Cmd {
+	gotrace.Trace("RUNNING: git %s", strings.Join(redactCredentials(r.args(opts)), " "))
+	cmd := exec.CommandContext(ctx, "git", opts...)
+	if r.path != "." {
+		cmd.Dir = r.path
+	}
+	addEnv(cmd, r.auth.authEnv())
+	if ctx.Done() != nil {
+		// git then cannot prompt on the terminal.
+		killProcessGroup(cmd)
+	}
+	// Processes started by git may keep the output open after git is killed.
+	cmd.WaitDelay = killWaitDelay
+	return cmd
+}
+
+// join returns the path of a file given relative to the repository directory.
+func (r *Repo) join(name string) string {
+	if filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(r.path, name)
+}
+
+// gitPath returns the path of a file in the GIT directory, like "objects/pack".
+func (r *Repo) gitPath(name string) (string, error) {
+	v, err := r.Get("rev-parse", "--git-path", name)
+	if err != nil {
+		return "", err
+	}
+	return r.join(v), nil
+}
@@ -0,0 +1,180 @@
+package git
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestRepoArgs(t *testing.T) {
+	t.Log("Expecting Repo to set -C before the git command.")
+	tests := []struct {
+		path     string
+		expected []string
+	}{
+		{"", []string{"status", "--porcelain"}},
+		{".", []string{"status", "--porcelain"}},
+		{"/tmp/repo", []string{"-C", "/tmp/repo", "status", "--porcelain"}},
+	}
+
+	for _, test := range tests {
+		// Run the function
+		v := NewRepo(test.path).args([]string{"status", "--porcelain"})
+
+		// Test the result
+		if !reflect.DeepEqual(v, test.expected) {
+			t.Errorf("Expected NewRepo(%q) options to be %q. Got %q.", test.path, test.expected, v)
+		}
+	}
+}
+
+func TestRepoCommandDir(t *testing.T) {
+	t.Log("Expecting Repo to run git in the repository directory.")
+	tests := map[string]string{"": "", ".": "", "/tmp/repo": "/tmp/repo"}
+
+	for repoPath, expected := range tests {
+		// Run the function
+		cmd := NewRepo(repoPath).command("status")
+
+		// Test the result
+		if cmd.Dir != expected {
+			t.Errorf("Expected NewRepo(%q) to run git in %q. Got %q.", repoPath, expected, cmd.Dir)
+		}
+		if v := cmd.Args; !reflect.DeepEqual(v, []string{"git", "status"}) {
+			t.Errorf("Expected the command to be 'git status'. Got %q.", v)
+		}
+	}
+}
+
+func TestRepoConcurrent(t *testing.T) {
+	t.Log("Expecting several repositories to be used from goroutines at the same time.")
+	repos := make([]*Repo, 3)
+	for i := range repos {
+		dir, err := ioutil.TempDir("", "go-git-test")
+		if err != nil {
+			t.Fatalf("Unable to create a temporary directory. %s", err)
+		}
+		defer os.RemoveAll(dir)
+		initRemoteRepo(t, dir)
+		repos[i] = NewRepo(dir)
+	}
+	SetLogFunc(func(string) {})
+	defer SetLogFunc(logOut)
+
+	// Run the function
+	t.Log("Running Add() and Commit() on every repository concurrently...")
+	errs := make(chan error, len(repos))
+	var wg sync.WaitGroup
+	for _, repo := range repos {
+		wg.Add(1)
+		go func(repo *Repo) {
+			defer wg.Done()
+			for i := 0; i < 5; i++ {
+				file := "file" + strconv.Itoa(i)
+				if err := ioutil.WriteFile(path.Join(repo.Path(), file), []byte(repo.Path()), 0644); err != nil {
+					errs <- err
+					return
+				}
+				if repo.Add([]string{file}) > 0 {
+					errs <- fmt.Errorf("Unable to add '%s' in '%s'", file, repo.Path())
+					return
+				}
+				if err := repo.Commit(file, true); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(repo)
+	}
+	wg.Wait()
+	close(errs)
+
+	// Test the result
+	for err := range errs {
+		t.Errorf("Expected no error. Got %s.", err)
+	}
+	for _, repo := range repos {
+		if v := runGitIn(t, repo.Path(), "rev-list", "--count", "HEAD"); v != "6" {
+			t.Errorf("Expected 6 commits in '%s'. Got %s.", repo.Path(), v)
+		}
+		if v := runGitIn(t, repo.Path(), "status", "--porcelain"); v != "" {
+			t.Errorf("Expected '%s' to be clean. Got '%s'.", repo.Path(), v)
+		}
+	}
+}
+
+func TestRepoRunsInPath(t *testing.T) {
+	t.Log("Expecting Repo methods to work on the repository directory, not the current one.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	other, err := ioutil.TempDir("", "go-git-test")
+	if err != nil {
+		t.Fatalf("Unable to create a temporary directory. %s", err)
+	}
+	defer os.RemoveAll(other)
+	initRemoteRepo(t, other)
+	writeFile(t, path.Join(other, "new"), "1")
+	runGitIn(t, other, "add", "new")
+
+	repo := NewRepo(other)
+
+	// Run the function
+	t.Log("Running GetStatus() on the other repository...")
+	status := repo.GetStatus()
+
+	// Test the result
+	if status.Err != nil {
+		t.Fatalf("Expected no error. Got %s.", status.Err)
+	}
+	if v := status.Ready.Tracked(); len(v) != 1 || v[0] != "new" {
+		t.Errorf("Expected 'new' to be ready. Got %q.", v)
+	}
+	if v := GetStatus().CountFiles(); v != 0 {
+		t.Errorf("Expected the current repository to be clean. Got %d files.", v)
+	}
+
+	// Run the function
+	t.Log("Running Commit(\"second\", true) on the other repository...")
+	err = repo.Commit("second", true)
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v := runGitIn(t, other, "log", "-1", "--format=%s"); v != "second" {
+		t.Errorf("Expected the other repository last commit to be 'second'. Got '%s'.", v)
+	}
+	if v := runGit(t, "log", "-1", "--format=%s"); v != "first" {
+		t.Errorf("Expected the current repository last commit to be 'first'. Got '%s'.", v)
+	}
+}
+
+func TestEnsureRepoExistReturnsRepo(t *testing.T) {
+	t.Log("Expecting EnsureRepoExist to return the repository created.")
+	dir, done := initTestRepo(t)
+	defer done()
+
+	// Run the function
+	t.Log("Running EnsureRepoExist(\"new\")...")
+	repo, err := EnsureRepoExist(path.Join(dir, "new"))
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v := repo.Path(); v != path.Join(dir, "new") {
+		t.Errorf("Expected the repository path to be '%s'. Got '%s'.", path.Join(dir, "new"), v)
+	}
+	if v, err := repo.Get("rev-parse", "--show-toplevel"); err != nil {
+		t.Errorf("Expected no error. Got %s.", err)
+	} else if v != runGitIn(t, path.Join(dir, "new"), "rev-parse", "--show-toplevel") {
+		t.Errorf("Expected the repository top level to be the new repository. Got '%s'.", v)
+	}
+}
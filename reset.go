@@ -0,0 +1,85 @@
+package git
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ResetClean resets the index and the working tree to ref and removes untracked files and directories.
+// Ignored files are kept.
+//
+// WARNING: Any uncommitted work is lost.
+func (r *Repo) ResetClean(ref string) error {
+	if r.Do("reset", "-q", "--hard", ref) > 0 {
+		return fmt.Errorf("Unable to reset to '%s'", ref)
+	}
+	if r.Do("clean", "-q", "-f", "-d") > 0 {
+		return fmt.Errorf("Unable to remove untracked files")
+	}
+	return nil
+}
+
+// SquashRange replaces the commits after from up to HEAD by a single commit with message.
+// The index and the working tree are kept as is. If the commit fails, HEAD is restored.
+func (r *Repo) SquashRange(from string, message string) error {
+	head, err := r.Get("rev-parse", "--verify", "-q", "HEAD")
+	if err != nil {
+		return fmt.Errorf("Unable to find HEAD. %s", err)
+	}
+	if r.Do("reset", "-q", "--soft", from) > 0 {
+		return fmt.Errorf("Unable to reset to '%s'", from)
+	}
+	if r.Do("commit", "-q", "-m", message) > 0 {
+		r.Do("reset", "-q", "--soft", head)
+		return fmt.Errorf("Unable to commit the squashed changes. HEAD restored to %s", head)
+	}
+	return nil
+}
+
+// CheckoutPaths restores the files paths from ref in the index and the working tree, in one git command.
+// HEAD is not changed. If a path does not exist in ref, nothing is restored.
+//
+// WARNING: Uncommitted changes of the paths are lost.
+func (r *Repo) CheckoutPaths(ref string, paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	if r.Do(append([]string{"checkout", ref, "--"}, paths...)...) > 0 {
+		return fmt.Errorf("Unable to restore %s from '%s'", strings.Join(paths, ", "), ref)
+	}
+	return nil
+}
+
+// CheckoutWouldConflict returns the files with local changes which a checkout of ref would overwrite,
+// sorted: tracked files changed between HEAD and ref and modified in the index or the working tree,
+// and untracked files which exist in ref. git checkout refuses to switch to ref if the list is not empty.
+func (r *Repo) CheckoutWouldConflict(ref string) ([]string, error) {
+	changed, err := r.getRaw("diff", "--name-only", "--no-renames", "-z", "HEAD", ref, "--")
+	if err != nil {
+		return nil, fmt.Errorf("Unable to compare HEAD with '%s'. %s", ref, err)
+	}
+	// Staged and not staged changes.
+	dirty, err := r.getRaw("diff", "--name-only", "--no-renames", "-z", "HEAD", "--")
+	if err != nil {
+		return nil, fmt.Errorf("Unable to list the local changes. %s", err)
+	}
+	local, err := r.untrackedFiles()
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range strings.Split(dirty, "\x00") {
+		if file != "" {
+			local[file] = true
+		}
+	}
+
+	files := make([]string, 0)
+	for _, file := range strings.Split(changed, "\x00") {
+		if local[file] {
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
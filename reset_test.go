@@ -0,0 +1,178 @@
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"reflect"
+	"testing"
+)
+
+func TestResetClean(t *testing.T) {
+	t.Log("Expecting ResetClean to restore a clean working tree at a ref.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	runGit(t, "tag", "clean")
+	commitFile(t, "file", "2", "second")
+	writeFile(t, "file", "dirty")
+	writeFile(t, "staged", "1")
+	runGit(t, "add", "staged")
+	writeFile(t, "untracked/file", "1")
+
+	// Run the function
+	t.Log("Running ResetClean(\"clean\")...")
+	err := ResetClean("clean")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v := runGit(t, "status", "--porcelain"); v != "" {
+		t.Errorf("Expected a clean status. Got '%s'.", v)
+	}
+	if v := runGit(t, "rev-parse", "HEAD"); v != runGit(t, "rev-parse", "clean") {
+		t.Errorf("Expected HEAD to be at 'clean'. Got %s.", v)
+	}
+	if v, _ := ioutil.ReadFile("file"); string(v) != "1" {
+		t.Errorf("Expected 'file' to contain '1'. Got '%s'.", v)
+	}
+	if _, err := os.Stat("untracked"); !os.IsNotExist(err) {
+		t.Errorf("Expected 'untracked' to be removed. Still exists.")
+	}
+}
+
+func TestSquashRange(t *testing.T) {
+	t.Log("Expecting SquashRange to squash commits into one.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	runGit(t, "tag", "base")
+	commitFile(t, "a", "1", "a")
+	commitFile(t, "b", "1", "b")
+	commitFile(t, "c", "1", "c")
+	tree := runGit(t, "rev-parse", "HEAD^{tree}")
+	writeFile(t, "untracked", "1")
+
+	// Run the function
+	t.Log("Running SquashRange(\"base\", \"squashed\")...")
+	err := SquashRange("base", "squashed")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v, err := CommitCount("HEAD"); err != nil {
+		t.Errorf("Expected no error. Got %s.", err)
+	} else if v != 2 {
+		t.Errorf("Expected 2 commits. Got %d.", v)
+	}
+	if v := runGit(t, "rev-parse", "HEAD^{tree}"); v != tree {
+		t.Errorf("Expected the squashed tree to be %s. Got %s.", tree, v)
+	}
+	if v := runGit(t, "log", "-1", "--format=%s"); v != "squashed" {
+		t.Errorf("Expected the commit message 'squashed'. Got '%s'.", v)
+	}
+	if v := runGit(t, "status", "--porcelain"); v != "?? untracked" {
+		t.Errorf("Expected the untracked file to be kept. Got '%s'.", v)
+	}
+}
+
+func TestCheckoutPaths(t *testing.T) {
+	t.Log("Expecting CheckoutPaths to restore several files from a ref.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	writeFile(t, "a", "1")
+	writeFile(t, "b", "1")
+	writeFile(t, "conf/c", "1")
+	writeFile(t, "d", "1")
+	runGit(t, "add", ".")
+	runGit(t, "commit", "-q", "-m", "release")
+	runGit(t, "tag", "v1")
+	for _, file := range []string{"a", "b", "conf/c", "d"} {
+		writeFile(t, file, "2")
+	}
+	runGit(t, "commit", "-q", "-a", "-m", "second")
+	head := runGit(t, "rev-parse", "HEAD")
+	writeFile(t, "a", "dirty")
+
+	// Run the function
+	t.Log("Running CheckoutPaths(\"v1\", [a b conf/c])...")
+	err := CheckoutPaths("v1", []string{"a", "b", "conf/c"})
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	for file, expected := range map[string]string{"a": "1", "b": "1", "conf/c": "1", "d": "2"} {
+		if v, _ := ioutil.ReadFile(file); string(v) != expected {
+			t.Errorf("Expected '%s' to contain '%s'. Got '%s'.", file, expected, v)
+		}
+	}
+	if v := runGit(t, "diff", "--cached", "--name-only"); v != "a\nb\nconf/c" {
+		t.Errorf("Expected the restored files to be staged. Got %q.", v)
+	}
+	if v := runGit(t, "rev-parse", "HEAD"); v != head {
+		t.Errorf("Expected HEAD to stay %s. Got %s.", head, v)
+	}
+
+	// Run the function
+	t.Log("Running CheckoutPaths(\"v1\", [b unknown])...")
+	if err = CheckoutPaths("v1", []string{"b", "unknown"}); err == nil {
+		t.Errorf("Expected an error. Got none.")
+	}
+}
+
+func TestCheckoutWouldConflict(t *testing.T) {
+	t.Log("Expecting CheckoutWouldConflict to list the local changes a checkout would overwrite.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	commitFile(t, "same", "1", "same")
+	commitFile(t, " leading space", "1", "leading space")
+	runGit(t, "checkout", "-q", "-b", "feature")
+	commitFile(t, "file", "feature", "feature")
+	commitFile(t, " leading space", "feature", "feature leading space")
+	commitFile(t, "staged", "feature", "staged")
+	commitFile(t, "new", "feature", "new")
+	runGit(t, "checkout", "-q", "master")
+
+	writeFile(t, "same", "dirty")
+
+	// Run the function
+	t.Log("Running CheckoutWouldConflict(\"feature\") with a change feature does not touch...")
+	files, err := CheckoutWouldConflict("feature")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if len(files) != 0 {
+		t.Errorf("Expected no conflicts. Got %q.", files)
+	}
+
+	writeFile(t, "file", "dirty")
+	writeFile(t, "staged", "dirty")
+	runGit(t, "add", "staged")
+	writeFile(t, "new", "untracked")
+	writeFile(t, " leading space", "dirty")
+
+	// Run the function
+	t.Log("Running CheckoutWouldConflict(\"feature\")...")
+	files, err = CheckoutWouldConflict("feature")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if expected := []string{" leading space", "file", "new", "staged"}; !reflect.DeepEqual(files, expected) {
+		t.Errorf("Expected %q. Got %q.", expected, files)
+	}
+	if err := exec.Command("git", "checkout", "-q", "feature").Run(); err == nil {
+		t.Errorf("Expected git checkout to refuse the checkout. Got no error.")
+	}
+}
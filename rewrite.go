@@ -0,0 +1,42 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/forj-oss/forjj-modules/trace"
+)
+
+// RemovePathFromHistory removes the file or directory path from every commit of every branch and tag,
+// with git filter-branch. Commits which only changed path are dropped.
+//
+// WARNING: the history is rewritten. All commits since the first one touching path get new hashes,
+// so the branches must be force-pushed and every other clone must be re-cloned or reset.
+// The backup refs created by git filter-branch (refs/original) are removed, but the old commits
+// stay reachable from the reflogs until they expire, for example with
+// "git reflog expire --expire=now --all" and "git gc --prune=now".
+// The working tree must be clean.
+func (r *Repo) RemovePathFromHistory(path string) error {
+	gotrace.Warning("Rewriting the whole history to remove '%s'. Every branch will have to be force-pushed.", path)
+	filter := "git rm -q -r --cached --ignore-unmatch -- " + shellQuote(path)
+	// FILTER_BRANCH_SQUELCH_WARNING skips the warning and delay of filter-branch.
+	if r.doWithEnv([]string{"FILTER_BRANCH_SQUELCH_WARNING=1"},
+		"filter-branch", "--force", "--index-filter", filter, "--prune-empty", "--tag-name-filter", "cat", "--", "--all") > 0 {
+		return fmt.Errorf("Unable to remove '%s' from the history", path)
+	}
+
+	v, err := r.Get("for-each-ref", "--format=%(refname)", "refs/original/")
+	if err != nil {
+		return fmt.Errorf("Unable to list the backup refs. %s", err)
+	}
+	updates := make([]RefUpdate, 0)
+	for _, ref := range strings.Fields(v) {
+		updates = append(updates, RefUpdate{Ref: ref})
+	}
+	return r.UpdateRefs(updates)
+}
+
+// shellQuote quotes text to be passed as a single argument in a shell command.
+func shellQuote(text string) string {
+	return "'" + strings.Replace(text, "'", `'\''`, -1) + "'"
+}
@@ -0,0 +1,47 @@
+package git
+
+import (
+	"os"
+	"testing"
+)
+
+func TestRemovePathFromHistory(t *testing.T) {
+	t.Log("Expecting RemovePathFromHistory to remove a file from every commit.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	commitFile(t, "it's secret", "password", "leak")
+	runGit(t, "tag", "-a", "-m", "v1", "v1")
+	commitFile(t, "file", "2", "second")
+	runGit(t, "checkout", "-q", "-b", "feature")
+	commitFile(t, "it's secret", "password2", "leak again")
+	runGit(t, "checkout", "-q", "master")
+
+	// Run the function
+	t.Log("Running RemovePathFromHistory(\"it's secret\")...")
+	err := RemovePathFromHistory("it's secret")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v := runGit(t, "log", "--all", "--format=%H", "--", "it's secret"); v != "" {
+		t.Errorf("Expected no commit to contain the file. Got %s.", v)
+	}
+	if v := runGit(t, "log", "--all", "--format=%s"); v != "second\nfirst" {
+		t.Errorf("Expected the commits which only added the file to be dropped. Got %q.", v)
+	}
+	if v := runGit(t, "for-each-ref", "refs/original/"); v != "" {
+		t.Errorf("Expected no backup refs. Got %s.", v)
+	}
+	if v := runGit(t, "cat-file", "-t", "v1"); v != "tag" {
+		t.Errorf("Expected the tag v1 to stay annotated. Got %s.", v)
+	}
+	if v := runGit(t, "show", "feature:file"); v != "2" {
+		t.Errorf("Expected feature to keep the content of file. Got '%s'.", v)
+	}
+	if _, err := os.Stat("it's secret"); err == nil {
+		t.Errorf("Expected the file to be removed from the working tree.")
+	}
+}
@@ -0,0 +1,189 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Runner runs the git commands of a Repo. See Repo.SetRunner.
+type Runner interface {
+	// Run runs cmd like cmd.Run. cmd.Stdin, cmd.Stdout and cmd.Stderr are set by the caller, if needed.
+	// A command which ran and failed returns an *exec.ExitError or an *Error with the exit code.
+	Run(cmd *exec.Cmd) error
+}
+
+// ExecRunner is the default Runner. It runs the git binary.
+type ExecRunner struct{}
+
+// Run runs cmd.
+func (ExecRunner) Run(cmd *exec.Cmd) error {
+	return cmd.Run()
+}
+
+// RecordedCommand is a git command recorded by DryRunRunner or RecordingRunner.
+type RecordedCommand struct {
+	// Args is the command line, like ["git", "status", "--porcelain"], with credentials masked.
+	Args []string
+	// Dir is the directory the command is run in. Empty for the current directory.
+	Dir string
+	// Stdin is the input given to the command, if any.
+	Stdin string
+}
+
+// recorder keeps the list of commands run.
+type recorder struct {
+	sync.Mutex
+	commands []RecordedCommand
+}
+
+// record adds cmd to the commands run. Its input is read and replaced by a copy.
+func (rec *recorder) record(cmd *exec.Cmd) RecordedCommand {
+	command := RecordedCommand{Args: redactCredentials(cmd.Args), Dir: cmd.Dir}
+	if cmd.Stdin != nil && cmd.Stdin != os.Stdin {
+		input, _ := io.ReadAll(cmd.Stdin)
+		command.Stdin = string(input)
+		cmd.Stdin = bytes.NewReader(input)
+	}
+
+	rec.Lock()
+	defer rec.Unlock()
+	rec.commands = append(rec.commands, command)
+	return command
+}
+
+// Commands returns the commands run, in order.
+func (rec *recorder) Commands() []RecordedCommand {
+	rec.Lock()
+	defer rec.Unlock()
+	return append([]RecordedCommand{}, rec.commands...)
+}
+
+// DryRunRunner records the git commands without running them, to preview what would be done.
+// Every command succeeds without output, so functions reading git output, like GetStatus,
+// find nothing.
+type DryRunRunner struct {
+	recorder
+}
+
+// Run records cmd.
+func (runner *DryRunRunner) Run(cmd *exec.Cmd) error {
+	runner.record(cmd)
+	return nil
+}
+
+// Response is the result of a command returned by RecordingRunner.
+type Response struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// RecordingRunner records the git commands and returns the responses defined with AddResponse.
+// Commands without response are run by Next, or succeed without output if Next is nil.
+// It is designed for unit tests of code using the package.
+type RecordingRunner struct {
+	recorder
+	Next      Runner
+	responses map[string][]Response
+}
+
+// AddResponse defines the response of the next git command with the options opts, like "status", "--porcelain".
+// Responses of the same command are returned in the order they were added. The last one is kept
+// for the later commands.
+func (runner *RecordingRunner) AddResponse(opts []string, response Response) {
+	runner.Lock()
+	defer runner.Unlock()
+	if runner.responses == nil {
+		runner.responses = make(map[string][]Response)
+	}
+	key := strings.Join(opts, "\x00")
+	runner.responses[key] = append(runner.responses[key], response)
+}
+
+// Run records cmd and writes its response on the command output.
+func (runner *RecordingRunner) Run(cmd *exec.Cmd) error {
+	command := runner.record(cmd)
+	response, found := runner.response(command.Args[1:])
+	if !found {
+		if runner.Next == nil {
+			return nil
+		}
+		return runner.Next.Run(cmd)
+	}
+
+	if cmd.Stdout != nil {
+		io.WriteString(cmd.Stdout, response.Stdout)
+	}
+	if cmd.Stderr != nil {
+		io.WriteString(cmd.Stderr, response.Stderr)
+	}
+	if response.ExitCode != 0 {
+		return &Error{Args: command.Args, ExitCode: response.ExitCode, Stdout: response.Stdout, Stderr: response.Stderr}
+	}
+	return nil
+}
+
+// response returns the next response of the command with the options opts.
+func (runner *RecordingRunner) response(opts []string) (Response, bool) {
+	runner.Lock()
+	defer runner.Unlock()
+	key := strings.Join(opts, "\x00")
+	responses := runner.responses[key]
+	if len(responses) == 0 {
+		return Response{}, false
+	}
+	if len(responses) > 1 {
+		runner.responses[key] = responses[1:]
+	}
+	return responses[0], true
+}
+
+// SetRunner defines how the git commands of the repository are run, like with a DryRunRunner.
+// A nil runner restores the ExecRunner.
+func (r *Repo) SetRunner(runner Runner) {
+	r.runner = runner
+}
+
+// runCommand runs cmd like cmd.Run with the runner of the repository, and reports it to the command observer.
+func (r *Repo) runCommand(cmd *exec.Cmd) error {
+	runner := r.runner
+	if runner == nil {
+		runner = ExecRunner{}
+	}
+	start := time.Now()
+	err := runner.Run(cmd)
+	observeCommand(cmd.Args, start, exitCode(err))
+	return err
+}
+
+// outputCommand runs cmd like cmd.Output with the runner of the repository.
+func (r *Repo) outputCommand(cmd *exec.Cmd) ([]byte, error) {
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	var stderr *bytes.Buffer
+	if cmd.Stderr == nil {
+		stderr = new(bytes.Buffer)
+		cmd.Stderr = stderr
+	}
+	err := r.runCommand(cmd)
+	var exitErr *exec.ExitError
+	if stderr != nil && errors.As(err, &exitErr) {
+		exitErr.Stderr = stderr.Bytes()
+	}
+	return stdout.Bytes(), err
+}
+
+// combinedOutputCommand runs cmd like cmd.CombinedOutput with the runner of the repository.
+func (r *Repo) combinedOutputCommand(cmd *exec.Cmd) ([]byte, error) {
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := r.runCommand(cmd)
+	return out.Bytes(), err
+}
@@ -0,0 +1,94 @@
+package git
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDryRunRunner(t *testing.T) {
+	t.Log("Expecting DryRunRunner to record the git commands without running them.")
+	dir, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	writeFile(t, "file", "2")
+	runner := &DryRunRunner{}
+	repo := NewRepo(dir)
+	repo.SetRunner(runner)
+
+	// Run the function
+	t.Log("Running Add() and Push() with a DryRunRunner...")
+	addCode := repo.Add([]string{"file"})
+	pushErr := repo.PushTags("origin")
+
+	// Test the result
+	if addCode != 0 || pushErr != nil {
+		t.Errorf("Expected the commands to succeed. Got %d and %v.", addCode, pushErr)
+	}
+	expected := []RecordedCommand{
+		{Args: []string{"git", "add", "file"}, Dir: dir},
+		{Args: []string{"git", "push", "origin", "--tags"}, Dir: dir},
+	}
+	if v := runner.Commands(); !reflect.DeepEqual(v, expected) {
+		t.Errorf("Expected %+v. Got %+v.", expected, v)
+	}
+	if v := runGit(t, "diff", "--cached", "--name-only"); v != "" {
+		t.Errorf("Expected nothing to be staged. Got '%s'.", v)
+	}
+}
+
+func TestRecordingRunner(t *testing.T) {
+	t.Log("Expecting RecordingRunner to return the responses defined and to run the other commands.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	runner := &RecordingRunner{Next: ExecRunner{}}
+	runner.AddResponse([]string{"status", "--porcelain=v2", "-z"}, Response{Stdout: "? untracked\x00"})
+	runner.AddResponse([]string{"status", "--porcelain=v2", "-z"}, Response{})
+	runner.AddResponse([]string{"merge", "feature"}, Response{Stdout: "CONFLICT (content): Merge conflict in file\n", ExitCode: 1})
+	SetRunner(runner)
+	defer SetRunner(nil)
+
+	// Run the function
+	t.Log("Running GetStatus() 3 times...")
+	first, second, third := GetStatus(), GetStatus(), GetStatus()
+
+	// Test the result
+	if v := first.Untracked(); len(v) != 1 || v[0] != "untracked" {
+		t.Errorf("Expected the first response. Got %q.", v)
+	}
+	if !second.IsClean() || !third.IsClean() {
+		t.Errorf("Expected the last response to be kept. Got %+v and %+v.", second.Entries, third.Entries)
+	}
+
+	// Run the function
+	t.Log("Running Get(\"merge\", \"feature\") with a failing response...")
+	_, err := Get("merge", "feature")
+
+	// Test the result
+	var gitErr *Error
+	if !errors.As(err, &gitErr) || gitErr.ExitCode != 1 || !IsMergeConflict(err) {
+		t.Errorf("Expected a conflict. Got %v.", err)
+	}
+
+	// Run the function
+	t.Log("Running getWithInput() without response...")
+	hash, err := defaultRepo.getWithInput("content", "hash-object", "--stdin")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if hash != "6b584e8ece562ebffc15d38808cd6b98fc3d97ea" {
+		t.Errorf("Expected the hash computed by git. Got '%s'.", hash)
+	}
+	commands := runner.Commands()
+	if v := len(commands); v != 5 {
+		t.Fatalf("Expected 5 commands recorded. Got %d.", v)
+	}
+	if v := commands[4]; v.Stdin != "content" || !reflect.DeepEqual(v.Args, []string{"git", "hash-object", "--stdin"}) {
+		t.Errorf("Expected hash-object with its input to be recorded. Got %+v.", v)
+	}
+}
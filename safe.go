@@ -0,0 +1,109 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// safeCommands are the git commands accepted by RunSafe.
+// Commands able to run arbitrary programs or change the configuration, like config or submodule, are not listed.
+var safeCommands = map[string]bool{
+	// Read
+	"blame": true, "branch": true, "cat-file": true, "describe": true, "diff": true, "for-each-ref": true,
+	"grep": true, "log": true, "ls-files": true, "ls-tree": true, "merge-base": true, "rev-list": true,
+	"rev-parse": true, "shortlog": true, "show": true, "show-ref": true, "status": true, "tag": true,
+	// Write
+	"add": true, "checkout": true, "cherry-pick": true, "commit": true, "fetch": true, "merge": true,
+	"mv": true, "pull": true, "push": true, "rebase": true, "reset": true, "restore": true,
+	"revert": true, "rm": true, "stash": true, "switch": true,
+}
+
+// unsafeOptions are the options running a program, or reading or writing a file, given on the command line.
+// Options listed for "" apply to every command. Long options also match their abbreviations, like "--exe",
+// and short options match when bundled with others, like "-ix".
+var unsafeOptions = map[string][]string{
+	"": {"--upload-pack", "--receive-pack", "--exec", "--output", "--no-index", "--pathspec-from-file",
+		"--open-files-in-pager"},
+	"blame": {"--contents", "-S", "--ignore-revs-file"},
+	// -s: a custom merge strategy runs the git-merge-<strategy> program.
+	"cherry-pick": {"-s", "--strategy"},
+	"commit":      {"-F", "--file", "-t", "--template"},
+	"fetch":       {"--multiple"},
+	"grep":        {"-O", "-f"},
+	"ls-files":    {"-X", "--exclude-from"},
+	"merge":       {"-F", "--file", "-s", "--strategy"},
+	"pull":        {"-s", "--strategy"},
+	"push":        {"--repo"},
+	"rebase":      {"-x", "-s", "--strategy"},
+	"revert":      {"-s", "--strategy"},
+	"tag":         {"-F", "--file"},
+}
+
+// remoteCommands are the commands of safeCommands which first argument, if any, is a remote.
+var remoteCommands = map[string]bool{"fetch": true, "pull": true, "push": true}
+
+// unsafeOption returns the option of unsafeOptions for subcommand which arg enables, if any.
+func unsafeOption(subcommand, arg string) (string, bool) {
+	for _, options := range [][]string{unsafeOptions[""], unsafeOptions[subcommand]} {
+		for _, option := range options {
+			if strings.HasPrefix(option, "--") {
+				name := strings.SplitN(arg, "=", 2)[0]
+				if len(name) > 2 && strings.HasPrefix(option, name) {
+					return option, true
+				}
+			} else if len(arg) > 1 && arg[0] == '-' && arg[1] != '-' && strings.Contains(arg[1:], option[1:]) {
+				return option, true
+			}
+		}
+	}
+	return "", false
+}
+
+// RunSafe runs a git command given by a user, if subcommand is a known git command,
+// and none of args can make git run another program, or read or write a file outside of the repository.
+// Arguments looking like an unsafe option are rejected, even if used as a value, like a commit message.
+// fetch, pull and push only accept a configured remote name as first argument, not a url or a path.
+// Arguments are given to git as is, no shell is involved.
+// It returns the command output, and its exit code. An error is returned if the command is
+// rejected or could not be run.
+func (r *Repo) RunSafe(subcommand string, args ...string) (stdout string, code int, err error) {
+	if strings.HasPrefix(subcommand, "-") || !safeCommands[subcommand] {
+		return "", -1, fmt.Errorf("Unable to run 'git %s'. This command is not allowed", subcommand)
+	}
+	for _, arg := range args {
+		if option, found := unsafeOption(subcommand, arg); found {
+			return "", -1, fmt.Errorf("Unable to run 'git %s'. The option '%s' (%s) is not allowed", subcommand, arg, option)
+		}
+	}
+	if remoteCommands[subcommand] {
+		if err := r.checkRemoteArg(subcommand, args); err != nil {
+			return "", -1, err
+		}
+	}
+
+	stdout, err = r.Get(append([]string{subcommand}, args...)...)
+	if code = exitCode(err); code < 0 {
+		return stdout, code, err
+	}
+	return stdout, code, nil
+}
+
+// checkRemoteArg returns an error if the first argument of args which is not an option is not a configured remote.
+func (r *Repo) checkRemoteArg(subcommand string, args []string) error {
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			continue
+		}
+		remotes, err := r.Remotes()
+		if err != nil {
+			return fmt.Errorf("Unable to list the remotes. %s", err)
+		}
+		for _, remote := range remotes {
+			if arg == remote {
+				return nil
+			}
+		}
+		return fmt.Errorf("Unable to run 'git %s'. '%s' is not a configured remote", subcommand, arg)
+	}
+	return nil
+}
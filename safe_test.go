@@ -0,0 +1,97 @@
+package git
+
+import (
+	"testing"
+)
+
+func TestRunSafe(t *testing.T) {
+	t.Log("Expecting RunSafe to run known git commands and reject unsafe ones.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	runGit(t, "remote", "add", "origin", "https://example.com/repo.git")
+
+	// Run the function
+	t.Logf("Running RunSafe(\"log\", \"-1\", \"--format=%%s\")...")
+	stdout, code, err := RunSafe("log", "-1", "--format=%s")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if code != 0 || stdout != "first" {
+		t.Errorf("Expected 'first' and exit code 0. Got '%s' and %d.", stdout, code)
+	}
+
+	// Run the function
+	t.Log("Running RunSafe(\"rev-parse\", \"--verify\", \"-q\", \"unknown\")...")
+	_, code, err = RunSafe("rev-parse", "--verify", "-q", "unknown")
+
+	// Test the result
+	if err != nil || code != 1 {
+		t.Errorf("Expected exit code 1 without error. Got %d, %v.", code, err)
+	}
+
+	rejected := [][]string{
+		{"-c", "alias.x=!touch injected", "x"},
+		{"--exec-path=/tmp", "status"},
+		{"config", "core.sshCommand", "touch injected"},
+		{"status; touch injected"},
+		{"fetch", "--upload-pack=touch injected", "origin"},
+		{"grep", "-Otouch", "first"},
+		{"rebase", "-x", "touch injected", "HEAD~1"},
+		{"rebase", "-ixtouch injected", "HEAD~1"},
+		{"rebase", "--exec=touch injected", "HEAD~1"},
+		{"rebase", "--exe", "touch injected", "HEAD~1"},
+		{"log", "--output=injected", "-1"},
+		{"log", "--output", "injected", "-1"},
+		{"show", "--outp=injected"},
+		{"diff", "--no-index", "/etc/hostname", "file"},
+		{"commit", "-F", "/etc/hostname"},
+		{"commit", "-F/etc/hostname"},
+		{"tag", "--file=/etc/hostname", "-a", "v1"},
+		{"add", "--pathspec-from-file=/etc/hostname"},
+		{"merge", "-s", "../../tmp/injected", "master"},
+		{"push", "--receive-pack", "touch injected", "origin"},
+		{"push", "--exec=touch injected", "origin"},
+		{"grep", "-f", "/etc/hostname"},
+		{"grep", "-if", "/etc/hostname"},
+		{"ls-files", "-X", "/etc/hostname"},
+		{"ls-files", "--exclude-from=/etc/hostname"},
+		{"blame", "--ignore-revs-file=/etc/hostname", "file"},
+		{"fetch", "/tmp"},
+		{"fetch", "--multiple", "origin", "/tmp"},
+		{"pull", "-q", "file:///tmp", "master"},
+		{"push", "../other", "master"},
+		{"push", "--repo=../other"},
+	}
+	for _, cmd := range rejected {
+		// Run the function
+		_, _, err = RunSafe(cmd[0], cmd[1:]...)
+
+		// Test the result
+		if err == nil {
+			t.Errorf("Expected %q to be rejected. Got no error.", cmd)
+		}
+	}
+	if v := runGit(t, "status", "--porcelain"); v != "" {
+		t.Errorf("Expected no file created. Got status '%s'.", v)
+	}
+
+	accepted := [][]string{
+		{"log", "-n", "1", "--oneline", "--stat"},
+		{"cherry-pick", "-x", "--abort"},
+		{"show", "-s", "HEAD"},
+		{"push", "-n", "origin", "unknown"},
+	}
+	for _, cmd := range accepted {
+		// Run the function
+		_, _, err = RunSafe(cmd[0], cmd[1:]...)
+
+		// Test the result
+		if err != nil {
+			t.Errorf("Expected %q to be accepted. Got %s.", cmd, err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Token identifies a repository state recorded by Snapshot.
+type Token struct {
+	head      string
+	branch    string
+	stash     string
+	untracked map[string]bool
+}
+
+// Snapshot records HEAD, the index and the working tree, so Rollback can restore them.
+// The changes are kept in a stash commit which is not added to the stash list,
+// and the repository is not updated.
+//
+// Untracked files are not recorded. Rollback only removes the ones created after Snapshot.
+func (r *Repo) Snapshot() (token Token, err error) {
+	if token.head, err = r.Get("rev-parse", "--verify", "-q", "HEAD"); err != nil {
+		return token, fmt.Errorf("Unable to find HEAD. %s", err)
+	}
+	// Empty if HEAD is detached.
+	token.branch, _ = r.Get("symbolic-ref", "-q", "--short", "HEAD")
+	// Empty if there is nothing to stash.
+	if token.stash, err = r.Get("stash", "create"); err != nil {
+		return token, fmt.Errorf("Unable to record the uncommitted changes. %s", err)
+	}
+	if token.untracked, err = r.untrackedFiles(); err != nil {
+		return token, err
+	}
+	return token, nil
+}
+
+// Rollback restores HEAD, the index and the working tree recorded by Snapshot.
+// The branch checked out at Snapshot is checked out again and reset to the recorded HEAD.
+// If HEAD was detached, it is detached again on the recorded commit. Other branches are not updated.
+//
+// WARNING: Any change done after Snapshot is lost.
+func (r *Repo) Rollback(token Token) error {
+	if token.head == "" {
+		return fmt.Errorf("Unable to rollback. The token was not created by Snapshot")
+	}
+	if token.branch == "" {
+		if r.Do("checkout", "-q", "-f", "--detach", token.head) > 0 {
+			return fmt.Errorf("Unable to checkout '%s'", token.head)
+		}
+	} else if r.Do("checkout", "-q", "-f", "-B", token.branch, token.head) > 0 {
+		return fmt.Errorf("Unable to reset '%s' to '%s'", token.branch, token.head)
+	}
+	if token.stash != "" && r.Do("stash", "apply", "-q", "--index", token.stash) > 0 {
+		return fmt.Errorf("Unable to restore the uncommitted changes from '%s'", token.stash)
+	}
+
+	untracked, err := r.untrackedFiles()
+	if err != nil {
+		return err
+	}
+	for file := range untracked {
+		if token.untracked[file] {
+			continue
+		}
+		if err := os.Remove(r.join(file)); err != nil {
+			return fmt.Errorf("Unable to remove '%s'. %s", file, err)
+		}
+	}
+	return nil
+}
+
+// untrackedFiles returns the untracked files which are not ignored.
+func (r *Repo) untrackedFiles() (map[string]bool, error) {
+	v, err := r.getRaw("ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return nil, fmt.Errorf("Unable to list untracked files. %s", err)
+	}
+
+	files := make(map[string]bool)
+	for _, file := range strings.Split(v, "\x00") {
+		if file != "" {
+			files[file] = true
+		}
+	}
+	return files, nil
+}
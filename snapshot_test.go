@@ -0,0 +1,102 @@
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestSnapshotRollback(t *testing.T) {
+	t.Log("Expecting Rollback to restore the state recorded by Snapshot.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "staged", "1", "first")
+	commitFile(t, "updated", "1", "second")
+	head := runGit(t, "rev-parse", "HEAD")
+	writeFile(t, "staged", "2")
+	runGit(t, "add", "staged")
+	writeFile(t, "updated", "2")
+	writeFile(t, "kept", "1")
+	status := runGit(t, "status", "--porcelain")
+
+	// Run the function
+	t.Log("Running Snapshot()...")
+	token, err := Snapshot()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v := runGit(t, "status", "--porcelain"); v != status {
+		t.Errorf("Expected the repository to be left untouched. Got status %q.", v)
+	}
+
+	runGit(t, "add", "updated")
+	runGit(t, "commit", "-q", "-m", "third")
+	writeFile(t, "staged", "3")
+	writeFile(t, "created", "1")
+	writeFile(t, " leading space", "1")
+
+	// Run the function
+	t.Log("Running Rollback(token)...")
+	err = Rollback(token)
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v := runGit(t, "rev-parse", "HEAD"); v != head {
+		t.Errorf("Expected HEAD to be restored to %s. Got %s.", head, v)
+	}
+	if v := runGit(t, "status", "--porcelain"); v != status {
+		t.Errorf("Expected status %q. Got %q.", status, v)
+	}
+	for file, content := range map[string]string{"staged": "2", "updated": "2", "kept": "1"} {
+		if v, _ := ioutil.ReadFile(file); string(v) != content {
+			t.Errorf("Expected '%s' to contain '%s'. Got '%s'.", file, content, v)
+		}
+	}
+	for _, file := range []string{"created", " leading space"} {
+		if _, err := os.Stat(file); !os.IsNotExist(err) {
+			t.Errorf("Expected '%s' to be removed. Got %v.", file, err)
+		}
+	}
+}
+
+func TestRollbackOtherBranch(t *testing.T) {
+	t.Log("Expecting Rollback to restore the branch checked out at Snapshot.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	head := runGit(t, "rev-parse", "HEAD")
+	runGit(t, "checkout", "-q", "-b", "feature")
+	commitFile(t, "file", "2", "second")
+	feature := runGit(t, "rev-parse", "HEAD")
+	runGit(t, "checkout", "-q", "master")
+
+	token, err := Snapshot()
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	runGit(t, "checkout", "-q", "feature")
+
+	// Run the function
+	t.Log("Running Rollback(token) on another branch...")
+	err = Rollback(token)
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v := runGit(t, "symbolic-ref", "--short", "HEAD"); v != "master" {
+		t.Errorf("Expected 'master' to be checked out. Got '%s'.", v)
+	}
+	if v := runGit(t, "rev-parse", "HEAD"); v != head {
+		t.Errorf("Expected HEAD to be restored to %s. Got %s.", head, v)
+	}
+	if v := runGit(t, "rev-parse", "feature"); v != feature {
+		t.Errorf("Expected 'feature' to stay on %s. Got %s.", feature, v)
+	}
+}
@@ -0,0 +1,26 @@
+package git
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// StashFindByMessage returns the index of the most recent stash which message contains substr.
+func (r *Repo) StashFindByMessage(substr string) (index int, found bool, err error) {
+	v, err := r.Get("stash", "list", "--format=%gd %gs")
+	if err != nil || v == "" {
+		return
+	}
+
+	stashRE, _ := regexp.Compile(`^stash@\{(\d+)\} (.*)$`)
+	for _, line := range strings.Split(v, "\n") {
+		m := stashRE.FindStringSubmatch(line)
+		if m == nil || !strings.Contains(m[2], substr) {
+			continue
+		}
+		index, _ = strconv.Atoi(m[1])
+		return index, true, nil
+	}
+	return
+}
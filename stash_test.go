@@ -0,0 +1,39 @@
+package git
+
+import (
+	"testing"
+)
+
+func TestStashFindByMessage(t *testing.T) {
+	t.Log("Expecting StashFindByMessage to locate a stash by its message.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "0", "first")
+	for i, msg := range []string{"build cache", "release notes", "local tweaks"} {
+		writeFile(t, "file", string(rune('1'+i)))
+		runGit(t, "stash", "push", "-q", "-m", msg)
+	}
+
+	// Run the function
+	t.Log("Running StashFindByMessage(\"release\")...")
+	index, found, err := StashFindByMessage("release")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if !found {
+		t.Errorf("Expected the stash to be found. Not found.")
+	} else if index != 1 {
+		t.Errorf("Expected the stash index to be 1. Got %d.", index)
+	}
+
+	// Run the function
+	t.Log("Running StashFindByMessage(\"unknown\")...")
+	if _, found, err = StashFindByMessage("unknown"); err != nil {
+		t.Errorf("Expected no error. Got %s.", err)
+	} else if found {
+		t.Errorf("Expected no stash to be found. Found one.")
+	}
+}
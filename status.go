@@ -1,19 +1,80 @@
 package git
 
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
 // Status contains a representation of GIT status in porcelain mode.
+// Ready and NotReady list the files by state, as the first letter of the state in Entries.
 type Status struct {
 	Ready    gitFiles
 	NotReady gitFiles
-	Err      error
+	// Entries are the changes reported by git status, in its order.
+	Entries []StatusEntry
+	Err     error
 }
 
-// Files return all files updated identified by git status
-func (gs *Status) Files() (files []string) {
-	files = make([]string, gs.CountFiles())
+// States of a StatusEntry. The other states are the git letters: M (modified), T (type changed),
+// A (added), D (deleted), R (renamed), C (copied) and U (updated but unmerged).
+const (
+	StatusUnmodified = "."
+	StatusUntracked  = "?"
+)
 
-	files = append(files, gs.Ready.Files()...)
-	files = append(files, gs.NotReady.Files()...)
-	return
+// StatusEntry is a file reported by git status.
+type StatusEntry struct {
+	// Staged is the state of the file in the index compared to HEAD.
+	Staged string
+	// Unstaged is the state of the file in the working tree compared to the index.
+	// Both states are StatusUntracked for an untracked file. An untracked directory is
+	// reported as a single entry, with a Path ending with "/".
+	Unstaged string
+	Path     string
+	// OrigPath is the path in HEAD or in the index of a renamed or copied file. Empty otherwise.
+	OrigPath string
+	// Submodule is set if the entry is a submodule.
+	Submodule *SubmoduleState
+}
+
+// SubmoduleState describes the changes of a submodule entry.
+type SubmoduleState struct {
+	CommitChanged bool
+	Modified      bool
+	Untracked     bool
+}
+
+// IsConflict returns true if the entry is unmerged, like after a conflicting merge.
+func (e StatusEntry) IsConflict() bool {
+	switch e.Staged + e.Unstaged {
+	case "DD", "AU", "UD", "UA", "DU", "AA", "UU":
+		return true
+	}
+	return false
+}
+
+// IsUntracked returns true if the entry is an untracked file or directory.
+func (e StatusEntry) IsUntracked() bool {
+	return e.Staged == StatusUntracked
+}
+
+// IsStaged returns true if the entry has changes ready to be committed.
+func (e StatusEntry) IsStaged() bool {
+	return e.Staged != StatusUnmodified && !e.IsUntracked() && !e.IsConflict()
+}
+
+// IsClean returns true if git status reported no changes, untracked files included.
+func (gs *Status) IsClean() bool {
+	return gs.Err == nil && len(gs.Entries) == 0
+}
+
+// Files return all files updated identified by git status
+func (gs *Status) Files() []string {
+	return gs.paths(func(StatusEntry) bool { return true })
 }
 
 // CountFiles returns the number of files updated tracked or not.
@@ -22,12 +83,8 @@ func (gs *Status) CountFiles() int {
 }
 
 // Tracked return Tracked files
-func (gs *Status) Tracked() (files []string) {
-	files = make([]string, gs.CountTracked())
-
-	files = append(files, gs.Ready.Tracked()...)
-	files = append(files, gs.NotReady.Tracked()...)
-	return
+func (gs *Status) Tracked() []string {
+	return gs.paths(func(e StatusEntry) bool { return !e.IsUntracked() })
 }
 
 // CountTracked returns the number of tracked files updated in ready or not ready area.
@@ -35,13 +92,9 @@ func (gs *Status) CountTracked() int {
 	return gs.Ready.CountTracked() + gs.NotReady.CountTracked()
 }
 
-// Untracked return Tracked files
-func (gs *Status) Untracked() (files []string) {
-	files = make([]string, gs.CountUntracked())
-
-	files = append(files, gs.Ready.Untracked()...)
-	files = append(files, gs.NotReady.Untracked()...)
-	return
+// Untracked return the untracked files and directories.
+func (gs *Status) Untracked() []string {
+	return gs.paths(StatusEntry.IsUntracked)
 }
 
 // CountUntracked returns the number of tracked files updated in ready or not ready area.
@@ -49,6 +102,149 @@ func (gs *Status) CountUntracked() int {
 	return gs.NotReady.CountUntracked()
 }
 
+// Staged returns the files with changes ready to be committed.
+func (gs *Status) Staged() []string {
+	return gs.paths(StatusEntry.IsStaged)
+}
+
+// Conflicts returns the unmerged files. They must be resolved before committing.
+func (gs *Status) Conflicts() []string {
+	return gs.paths(StatusEntry.IsConflict)
+}
+
+func (gs *Status) paths(selected func(StatusEntry) bool) []string {
+	files := make([]string, 0)
+	for _, entry := range gs.Entries {
+		if selected(entry) {
+			files = append(files, entry.Path)
+		}
+	}
+	return files
+}
+
+// statusCache keeps the last Status computed by GetStatusCached and the index state it matches.
+type statusCache struct {
+	sync.Mutex
+	dir     string
+	modTime time.Time
+	size    int64
+	status  *Status
+}
+
+// GetStatusCached returns the same result as GetStatus, but git status is run again only
+// if the index file changed since the last call, or after InvalidateStatusCache.
+// Working tree changes which do not update the index are not detected.
+func (r *Repo) GetStatusCached() *Status {
+	// The default repository follows the current directory.
+	dir, _ := filepath.Abs(r.path)
+	// The index is not in .git for worktrees, submodules or with GIT_DIR.
+	index, err := r.gitPath("index")
+	if err != nil {
+		return r.GetStatus()
+	}
+	fi, err := os.Stat(index)
+	if err != nil {
+		return r.GetStatus()
+	}
+
+	r.statusCache.Lock()
+	defer r.statusCache.Unlock()
+	if r.statusCache.status != nil && r.statusCache.dir == dir &&
+		r.statusCache.modTime.Equal(fi.ModTime()) && r.statusCache.size == fi.Size() {
+		return r.statusCache.status
+	}
+
+	status := r.GetStatus()
+	if status.Err == nil {
+		// git status may refresh the index.
+		if fi, err = os.Stat(index); err == nil {
+			r.statusCache.dir, r.statusCache.modTime, r.statusCache.size = dir, fi.ModTime(), fi.Size()
+			r.statusCache.status = status
+		}
+	}
+	return status
+}
+
+// InvalidateStatusCache forces the next GetStatusCached call to run git status.
+func (r *Repo) InvalidateStatusCache() {
+	r.statusCache.Lock()
+	defer r.statusCache.Unlock()
+	r.statusCache.status = nil
+}
+
+// parsePorcelainV2 sets the entries from the output of git status --porcelain=v2 -z:
+//
+//	1 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <path>
+//	2 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <X><score> <path> NUL <origPath>
+//	u <XY> <sub> <m1> <m2> <m3> <mW> <h1> <h2> <h3> <path>
+//	? <path>
+//
+// Each record ends with NUL. Ignored files and headers are skipped.
+func (gs *Status) parsePorcelainV2(output string) error {
+	records := strings.Split(output, "\x00")
+	for i := 0; i < len(records); i++ {
+		record := records[i]
+		if record == "" {
+			continue
+		}
+		var entry StatusEntry
+		count := 0
+		switch record[0] {
+		case '1':
+			count = 9
+		case '2':
+			count = 10
+		case 'u':
+			count = 11
+		case '?':
+			entry = StatusEntry{Staged: StatusUntracked, Unstaged: StatusUntracked, Path: record[2:]}
+		default:
+			// '!' ignored and '#' headers
+			continue
+		}
+		if count > 0 {
+			fields := strings.SplitN(record, " ", count)
+			if len(fields) < count || len(fields[1]) != 2 {
+				return fmt.Errorf("Unable to parse the git status entry '%s'", record)
+			}
+			entry.Staged, entry.Unstaged = fields[1][0:1], fields[1][1:2]
+			entry.Path = fields[len(fields)-1]
+			if sub := fields[2]; sub[0] == 'S' && len(sub) == 4 {
+				entry.Submodule = &SubmoduleState{CommitChanged: sub[1] == 'C', Modified: sub[2] == 'M', Untracked: sub[3] == 'U'}
+			}
+			if record[0] == '2' {
+				if i++; i >= len(records) {
+					return fmt.Errorf("Unable to find the original path of '%s'", entry.Path)
+				}
+				entry.OrigPath = records[i]
+			}
+		}
+		gs.Entries = append(gs.Entries, entry)
+		gs.addEntry(entry)
+	}
+	return nil
+}
+
+// addEntry adds the entry file in the ready and not ready areas. Untracked files are in the not ready area.
+func (gs *Status) addEntry(entry StatusEntry) {
+	if entry.IsUntracked() {
+		gs.NotReady.add(StatusUntracked, entry.Path)
+		return
+	}
+	if entry.Staged != StatusUnmodified {
+		gs.Ready.add(entry.Staged, entry.Path)
+	}
+	if entry.Unstaged != StatusUnmodified {
+		gs.NotReady.add(entry.Unstaged, entry.Path)
+	}
+}
+
+// NativePath returns a file path as reported by git, with forward slashes, using the separator
+// of the operating system. It can then be compared with filepath results.
+func NativePath(file string) string {
+	return filepath.FromSlash(file)
+}
+
 type gitFiles map[string][]string
 
 // Files returns the list of files identified for the GIT area choosen.
@@ -61,7 +257,6 @@ func (gs gitFiles) Files() (files []string) {
 			files[count] = file
 			count++
 		}
-		count += len(list)
 	}
 	return
 }
@@ -88,7 +283,6 @@ func (gs gitFiles) Tracked() (files []string) {
 			files[count] = file
 			count++
 		}
-		count += len(list)
 	}
 	return
 }
@@ -106,7 +300,6 @@ func (gs gitFiles) Untracked() (files []string) {
 			files[count] = file
 			count++
 		}
-		count += len(list)
 	}
 	return
 }
@@ -1,6 +1,11 @@
 package git
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
 	"testing"
 )
 
@@ -67,3 +72,325 @@ func TestInit(t *testing.T) {
 		t.Errorf("Expected gitFiles to contains the 'D' element. Not found.")
 	}
 }
+
+func TestGetStatusCached(t *testing.T) {
+	t.Log("Expecting GetStatusCached to run git status only when the index changes.")
+	_, done := initTestRepo(t)
+	defer done()
+	defer InvalidateStatusCache()
+
+	commitFile(t, "file", "1", "first")
+
+	// Run the function
+	t.Log("Running GetStatusCached() twice...")
+	first := GetStatusCached()
+	second := GetStatusCached()
+
+	// Test the result
+	if first.Err != nil {
+		t.Fatalf("Expected no error. Got %s.", first.Err)
+	}
+	if first != second {
+		t.Errorf("Expected the second call to return the cached status. Got a new one.")
+	}
+
+	writeFile(t, "file", "2")
+	runGit(t, "add", "file")
+
+	// Run the function
+	t.Log("Running GetStatusCached() after an index update...")
+	third := GetStatusCached()
+
+	// Test the result
+	if third == second {
+		t.Errorf("Expected a new status after an index update. Got the cached one.")
+	}
+
+	// Run the function
+	t.Log("Running GetStatusCached() after InvalidateStatusCache()...")
+	InvalidateStatusCache()
+	if GetStatusCached() == third {
+		t.Errorf("Expected a new status after an invalidation. Got the cached one.")
+	}
+
+	worktreeDir, err := ioutil.TempDir("", "go-git-test")
+	if err != nil {
+		t.Fatalf("Unable to create a temporary directory. %s", err)
+	}
+	defer os.RemoveAll(worktreeDir)
+	worktreeDir = filepath.Join(worktreeDir, "worktree")
+	runGit(t, "worktree", "add", "-q", "-b", "feature", worktreeDir)
+	worktree := NewRepo(worktreeDir)
+
+	// Run the function
+	t.Log("Running GetStatusCached() twice in a worktree...")
+	first, second = worktree.GetStatusCached(), worktree.GetStatusCached()
+
+	// Test the result
+	if first.Err != nil {
+		t.Fatalf("Expected no error. Got %s.", first.Err)
+	}
+	if first != second {
+		t.Errorf("Expected the second call to return the cached status in a worktree. Got a new one.")
+	}
+}
+
+func TestParsePorcelainV2(t *testing.T) {
+	t.Log("Expecting parsePorcelainV2 to read the entries and dispatch them in the ready and not ready areas.")
+	const hash = "257cc5642cb1a054f08cc83f2d943e56fd3ebe99"
+	tests := []struct {
+		output   string
+		entry    StatusEntry
+		ready    map[string][]string
+		notReady map[string][]string
+	}{
+		{"1 M. N... 100644 100644 100644 " + hash + " " + hash + " staged\x00",
+			StatusEntry{Staged: "M", Unstaged: ".", Path: "staged"}, map[string][]string{"M": {"staged"}}, nil},
+		{"1 .M N... 100644 100644 100644 " + hash + " " + hash + " dir/a b\x00",
+			StatusEntry{Staged: ".", Unstaged: "M", Path: "dir/a b"}, nil, map[string][]string{"M": {"dir/a b"}}},
+		{"1 AD N... 000000 100644 000000 " + ZeroID + " " + hash + " added\x00",
+			StatusEntry{Staged: "A", Unstaged: "D", Path: "added"}, map[string][]string{"A": {"added"}}, map[string][]string{"D": {"added"}}},
+		{"2 RM N... 100644 100644 100644 " + hash + " " + hash + " R100 new\tname\x00old name\x00",
+			StatusEntry{Staged: "R", Unstaged: "M", Path: "new\tname", OrigPath: "old name"}, map[string][]string{"R": {"new\tname"}}, map[string][]string{"M": {"new\tname"}}},
+		{"2 C. N... 100644 100644 100644 " + hash + " " + hash + " C75 copy\x00orig\x00",
+			StatusEntry{Staged: "C", Unstaged: ".", Path: "copy", OrigPath: "orig"}, map[string][]string{"C": {"copy"}}, nil},
+		{"u UU N... 100644 100644 100644 100644 " + hash + " " + hash + " " + hash + " conflict\x00",
+			StatusEntry{Staged: "U", Unstaged: "U", Path: "conflict"}, map[string][]string{"U": {"conflict"}}, map[string][]string{"U": {"conflict"}}},
+		{"1 .M SC.U 160000 160000 160000 " + hash + " " + hash + " sub\x00",
+			StatusEntry{Staged: ".", Unstaged: "M", Path: "sub", Submodule: &SubmoduleState{CommitChanged: true, Untracked: true}}, nil, map[string][]string{"M": {"sub"}}},
+		{"? dir/\x00",
+			StatusEntry{Staged: "?", Unstaged: "?", Path: "dir/"}, nil, map[string][]string{"?": {"dir/"}}},
+	}
+
+	for _, test := range tests {
+		gs := &Status{Ready: make(gitFiles), NotReady: make(gitFiles)}
+
+		// Run the function
+		err := gs.parsePorcelainV2(test.output)
+
+		// Test the result
+		if err != nil {
+			t.Errorf("Expected no error for %q. Got %s.", test.output, err)
+			continue
+		}
+		if len(gs.Entries) != 1 || !reflect.DeepEqual(gs.Entries[0], test.entry) {
+			t.Errorf("Expected %q to be read as %+v. Got %+v.", test.output, test.entry, gs.Entries)
+		}
+		for _, area := range []struct {
+			name     string
+			files    gitFiles
+			expected map[string][]string
+		}{{"ready", gs.Ready, test.ready}, {"not ready", gs.NotReady, test.notReady}} {
+			if v := len(area.files); v != len(area.expected) {
+				t.Errorf("Expected %q to set %d states in the %s area. Got %v.", test.output, len(area.expected), area.name, area.files)
+				continue
+			}
+			for state, files := range area.expected {
+				if v := area.files[state]; len(v) != 1 || v[0] != files[0] {
+					t.Errorf("Expected %q to set %s as '%s' in the %s area. Got %q.", test.output, files, state, area.name, v)
+				}
+			}
+		}
+	}
+
+	// Run the function
+	gs := &Status{Ready: make(gitFiles), NotReady: make(gitFiles)}
+	err := gs.parsePorcelainV2("1 M. N... 100644\x00")
+
+	// Test the result
+	if err == nil {
+		t.Errorf("Expected an error for a truncated entry. Got none.")
+	}
+}
+
+func TestGetStatus(t *testing.T) {
+	t.Log("Expecting GetStatus to report staged, unstaged and untracked files.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "both", "1", "first")
+	commitFile(t, "old", "renamed content", "second")
+	writeFile(t, "both", "2")
+	runGit(t, "add", "both")
+	writeFile(t, "both", "3")
+	runGit(t, "mv", "old", "new")
+	writeFile(t, "untracked", "1")
+
+	// Run the function
+	t.Log("Running GetStatus()...")
+	gs := GetStatus()
+
+	// Test the result
+	if gs.Err != nil {
+		t.Fatalf("Expected no error. Got %s.", gs.Err)
+	}
+	tests := []struct {
+		area     string
+		files    gitFiles
+		state    string
+		expected []string
+	}{
+		{"ready", gs.Ready, "M", []string{"both"}},
+		{"ready", gs.Ready, "R", []string{"new"}},
+		{"not ready", gs.NotReady, "M", []string{"both"}},
+		{"not ready", gs.NotReady, "?", []string{"untracked"}},
+	}
+	for _, test := range tests {
+		if v := test.files[test.state]; len(v) != len(test.expected) || v[0] != test.expected[0] {
+			t.Errorf("Expected %q as '%s' in the %s area. Got %q.", test.expected, test.state, test.area, v)
+		}
+	}
+	if v := gs.Ready.CountTracked(); v != 2 {
+		t.Errorf("Expected 2 files ready. Got %d.", v)
+	}
+	if v := gs.NotReady.CountUntracked(); v != 1 {
+		t.Errorf("Expected 1 untracked file. Got %d.", v)
+	}
+	if v := gs.Staged(); !reflect.DeepEqual(v, []string{"both", "new"}) {
+		t.Errorf("Expected both and new to be staged. Got %q.", v)
+	}
+	if v := gs.Untracked(); !reflect.DeepEqual(v, []string{"untracked"}) {
+		t.Errorf("Expected untracked to be untracked. Got %q.", v)
+	}
+	for _, entry := range gs.Entries {
+		if entry.Path == "new" && entry.OrigPath != "old" {
+			t.Errorf("Expected new to be renamed from old. Got '%s'.", entry.OrigPath)
+		}
+	}
+	if gs.IsClean() {
+		t.Errorf("Expected the status not to be clean.")
+	}
+}
+
+func TestGitFilesLists(t *testing.T) {
+	t.Log("Expecting Files, Tracked and Untracked to list the files of every state.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "a", "1", "first")
+	commitFile(t, "c", "1", "second")
+	writeFile(t, "a", "2")
+	writeFile(t, "b", "1")
+	runGit(t, "add", "a", "b")
+	writeFile(t, "c", "2")
+	writeFile(t, "d", "1")
+
+	// Run the function
+	t.Log("Running GetStatus() with staged, modified and untracked files...")
+	gs := GetStatus()
+
+	// Test the result
+	if gs.Err != nil {
+		t.Fatalf("Expected no error. Got %s.", gs.Err)
+	}
+	tests := []struct {
+		name     string
+		files    []string
+		expected []string
+	}{
+		{"Ready.Files", gs.Ready.Files(), []string{"a", "b"}},
+		{"Ready.Tracked", gs.Ready.Tracked(), []string{"a", "b"}},
+		{"NotReady.Files", gs.NotReady.Files(), []string{"c", "d"}},
+		{"NotReady.Tracked", gs.NotReady.Tracked(), []string{"c"}},
+		{"NotReady.Untracked", gs.NotReady.Untracked(), []string{"d"}},
+	}
+	for _, test := range tests {
+		sort.Strings(test.files)
+		if !reflect.DeepEqual(test.files, test.expected) {
+			t.Errorf("Expected %s to return %q. Got %q.", test.name, test.expected, test.files)
+		}
+	}
+}
+
+func TestGetStatusConflicts(t *testing.T) {
+	t.Log("Expecting GetStatus to report the conflicts of a merge.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	commitFile(t, "other", "1", "other")
+
+	// Run the function
+	t.Log("Running GetStatus() on a clean repository...")
+	gs := GetStatus()
+
+	// Test the result
+	if !gs.IsClean() {
+		t.Errorf("Expected the status to be clean. Got %+v.", gs.Entries)
+	}
+
+	runGit(t, "checkout", "-q", "-b", "feature")
+	commitFile(t, "file", "feature", "feature")
+	runGit(t, "checkout", "-q", "master")
+	commitFile(t, "file", "master", "master")
+	Get("merge", "feature")
+	writeFile(t, "other", "2")
+
+	// Run the function
+	t.Log("Running GetStatus() after a conflicting merge...")
+	gs = GetStatus()
+
+	// Test the result
+	if gs.Err != nil {
+		t.Fatalf("Expected no error. Got %s.", gs.Err)
+	}
+	if v := gs.Conflicts(); !reflect.DeepEqual(v, []string{"file"}) {
+		t.Errorf("Expected file to be in conflict. Got %q.", v)
+	}
+	if v := gs.Staged(); len(v) != 0 {
+		t.Errorf("Expected no staged files. Got %q.", v)
+	}
+	tracked := gs.Tracked()
+	sort.Strings(tracked)
+	if v := tracked; !reflect.DeepEqual(v, []string{"file", "other"}) {
+		t.Errorf("Expected file and other to be changed. Got %q.", v)
+	}
+}
+
+func TestCommitNothingReady(t *testing.T) {
+	t.Log("Expecting Commit to report nothing to commit when only unstaged and untracked files exist.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	writeFile(t, "file", "2")
+	writeFile(t, "untracked", "1")
+
+	// Run the function
+	t.Log("Running Commit(\"second\", true)...")
+	err := Commit("second", true)
+
+	// Test the result
+	if err == nil {
+		t.Errorf("Expected an error as no files are ready. Got none.")
+	}
+	if v := runGit(t, "rev-list", "--count", "HEAD"); v != "1" {
+		t.Errorf("Expected 1 commit. Got %s.", v)
+	}
+}
+
+func TestGetStatusQuotedPath(t *testing.T) {
+	t.Log("Expecting GetStatus to unquote the paths with special characters.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	writeFile(t, "dir/a b\tc", "1")
+	runGit(t, "add", "dir")
+
+	// Run the function
+	t.Log("Running GetStatus()...")
+	status := GetStatus()
+
+	// Test the result
+	if status.Err != nil {
+		t.Fatalf("Expected no error. Got %s.", status.Err)
+	}
+	files := status.Ready.Tracked()
+	if len(files) != 1 || files[0] != "dir/a b\tc" {
+		t.Fatalf("Expected 'dir/a b\\tc' to be ready. Got %q.", files)
+	}
+	if v := NativePath(files[0]); v != filepath.Join("dir", "a b\tc") {
+		t.Errorf("Expected the native path to be %q. Got %q.", filepath.Join("dir", "a b\tc"), v)
+	}
+}
@@ -0,0 +1,64 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+// DriftedSubmodule is a submodule which checked out commit is not the one recorded by the superproject.
+type DriftedSubmodule struct {
+	Path string
+	// Recorded is the commit recorded in the superproject index.
+	Recorded string
+	// Actual is the commit checked out in the submodule.
+	Actual string
+}
+
+// SubmoduleDrift returns the submodules which HEAD differs from the commit recorded in the index,
+// ie changes which would not be part of the next Commit.
+// Submodules not checked out are ignored.
+func (r *Repo) SubmoduleDrift() ([]DriftedSubmodule, error) {
+	v, err := r.Get("ls-files", "-s", "-z")
+	if err != nil {
+		return nil, fmt.Errorf("Unable to list the index entries. %s", err)
+	}
+
+	drifts := make([]DriftedSubmodule, 0)
+	for _, entry := range strings.Split(v, "\x00") {
+		// <mode> SP <object> SP <stage> TAB <file>
+		fields := strings.SplitN(entry, "\t", 2)
+		info := strings.Fields(fields[0])
+		if len(fields) != 2 || len(info) != 3 || info[0] != "160000" {
+			continue
+		}
+		if _, err := os.Stat(r.join(path.Join(fields[1], ".git"))); err != nil {
+			continue
+		}
+		head, err := r.repoAt(r.join(fields[1])).Get("rev-parse", "HEAD")
+		if err != nil {
+			return drifts, fmt.Errorf("Unable to find the HEAD of the submodule '%s'. %s", fields[1], err)
+		}
+		if head != info[1] {
+			drifts = append(drifts, DriftedSubmodule{Path: fields[1], Recorded: info[1], Actual: head})
+		}
+	}
+	return drifts, nil
+}
+
+// SubmoduleShas returns the commit recorded at ref for each submodule, by submodule path.
+func (r *Repo) SubmoduleShas(ref string) (map[string]string, error) {
+	entries, err := r.ListTree(ref, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	shas := make(map[string]string)
+	for _, entry := range entries {
+		if entry.Mode == "160000" {
+			shas[entry.Path] = entry.Hash
+		}
+	}
+	return shas, nil
+}
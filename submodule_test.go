@@ -0,0 +1,86 @@
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestSubmoduleDrift(t *testing.T) {
+	t.Log("Expecting SubmoduleDrift to report a submodule moved from the recorded commit.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	remote, err := ioutil.TempDir("", "go-git-test")
+	if err != nil {
+		t.Fatalf("Unable to create a temporary directory. %s", err)
+	}
+	defer os.RemoveAll(remote)
+	initRemoteRepo(t, remote)
+	runGit(t, "-c", "protocol.file.allow=always", "submodule", "add", "-q", remote, "sub")
+	runGit(t, "commit", "-q", "-m", "add submodule")
+	recorded := runGitIn(t, "sub", "rev-parse", "HEAD")
+
+	// Run the function
+	t.Log("Running SubmoduleDrift() on a clean submodule...")
+	drifts, err := SubmoduleDrift()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v := len(drifts); v != 0 {
+		t.Errorf("Expected no drift. Got %+v.", drifts)
+	}
+
+	runGitIn(t, "sub", "config", "user.name", "Test User")
+	runGitIn(t, "sub", "config", "user.email", "test@example.com")
+	commitFileIn(t, "sub", "file", "1", "moved")
+	actual := runGitIn(t, "sub", "rev-parse", "HEAD")
+
+	// Run the function
+	t.Log("Running SubmoduleDrift() after a commit in the submodule...")
+	drifts, err = SubmoduleDrift()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	expected := DriftedSubmodule{Path: "sub", Recorded: recorded, Actual: actual}
+	if len(drifts) != 1 || drifts[0] != expected {
+		t.Errorf("Expected [%+v]. Got %+v.", expected, drifts)
+	}
+}
+
+func TestSubmoduleShas(t *testing.T) {
+	t.Log("Expecting SubmoduleShas to return the commit recorded for each submodule.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	first := runGit(t, "rev-parse", "HEAD")
+	commitFile(t, "file", "2", "second")
+	second := runGit(t, "rev-parse", "HEAD")
+	runGit(t, "update-index", "--add", "--cacheinfo", "160000,"+first+",libs/one")
+	runGit(t, "update-index", "--add", "--cacheinfo", "160000,"+second+",two")
+	runGit(t, "commit", "-q", "-m", "submodules")
+
+	// Run the function
+	t.Log("Running SubmoduleShas(\"HEAD\")...")
+	shas, err := SubmoduleShas("HEAD")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if expected := map[string]string{"libs/one": first, "two": second}; !reflect.DeepEqual(shas, expected) {
+		t.Errorf("Expected %v. Got %v.", expected, shas)
+	}
+
+	// Run the function
+	t.Log("Running SubmoduleShas(\"HEAD~1\")...")
+	if shas, err = SubmoduleShas("HEAD~1"); err != nil || len(shas) != 0 {
+		t.Errorf("Expected no submodules. Got %v, %v.", shas, err)
+	}
+}
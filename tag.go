@@ -0,0 +1,242 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoTags is returned when no tag can be found.
+var ErrNoTags = errors.New("No tags found")
+
+// Tag is a tag of the repository, returned by Tags.
+type Tag struct {
+	Name      string
+	Annotated bool
+	// Target is the object tagged, usually a commit. For an annotated tag, the tag object is skipped.
+	Target string
+	// Message is the message of an annotated tag. Empty for a lightweight tag.
+	Message string
+}
+
+// Tags returns the tags of the repository, sorted by name.
+func (r *Repo) Tags() ([]Tag, error) {
+	v, err := r.Get("for-each-ref", "--format=%(refname:short)%00%(objecttype)%00%(objectname)%00%(*objectname)%00%(contents)%1e", "refs/tags")
+	if err != nil {
+		return nil, fmt.Errorf("Unable to list the tags. %s", err)
+	}
+
+	tags := make([]Tag, 0)
+	for _, record := range strings.Split(v, "\x1e") {
+		record = strings.TrimLeft(record, "\n")
+		if record == "" {
+			continue
+		}
+		fields := strings.SplitN(record, "\x00", 5)
+		if len(fields) != 5 {
+			return tags, fmt.Errorf("Unable to parse the tag record '%s'", record)
+		}
+		tag := Tag{Name: fields[0], Target: fields[2]}
+		if fields[1] == "tag" {
+			tag.Annotated = true
+			tag.Target = fields[3]
+			tag.Message = strings.TrimRight(fields[4], "\n")
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// DeleteTag removes the local tag name. The tag is not removed from the remotes.
+func (r *Repo) DeleteTag(name string) error {
+	if r.Do("tag", "-d", name) > 0 {
+		return fmt.Errorf("Unable to delete the tag '%s'", name)
+	}
+	return nil
+}
+
+// PushTags pushes all local tags to remote.
+// If remote is empty, the default remote is used. See Repo.DefaultRemote.
+func (r *Repo) PushTags(remote string) error {
+	remote, err := r.resolveRemote(remote)
+	if err != nil {
+		return err
+	}
+	if err := r.doError(r.withCredentials("push", remote, "--tags")...); err != nil {
+		return fmt.Errorf("Unable to push the tags to '%s'. %w", remote, err)
+	}
+	return nil
+}
+
+// semverRE matches a semantic version, like 1.2.3, 1.2.3-rc.1 or 1.2.3+build.5, with an optional "v".
+var semverRE = regexp.MustCompile(`^v?(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+// LatestSemverTag returns the tag starting with prefix with the highest semantic version, like "v1.10.0"
+// for the prefix "v". The version is the rest of the tag name, with an optional "v".
+// Pre-releases, like 1.2.0-rc.1, are lower than the release. Tags which are not versions are ignored.
+// ErrNoTags is returned if no tag is a version.
+func (r *Repo) LatestSemverTag(prefix string) (string, error) {
+	tags, err := r.TagsSortedByVersion(prefix)
+	if err != nil {
+		return "", err
+	}
+
+	latest, latestVersion := "", []string(nil)
+	for _, tag := range tags {
+		version := semverRE.FindStringSubmatch(strings.TrimPrefix(tag, prefix))
+		if version == nil {
+			continue
+		}
+		if latestVersion == nil || compareSemver(version[1:5], latestVersion) > 0 {
+			latest, latestVersion = tag, version[1:5]
+		}
+	}
+	if latestVersion == nil {
+		return "", ErrNoTags
+	}
+	return latest, nil
+}
+
+// compareSemver compares the versions a and b given as major, minor, patch and pre-release,
+// as defined by semver.org. It returns -1, 0 or 1.
+func compareSemver(a, b []string) int {
+	for i := 0; i < 3; i++ {
+		if c := compareNumbers(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case a[3] == b[3]:
+		return 0
+	case a[3] == "":
+		return 1
+	case b[3] == "":
+		return -1
+	}
+
+	aIDs, bIDs := strings.Split(a[3], "."), strings.Split(b[3], ".")
+	for i := 0; i < len(aIDs) && i < len(bIDs); i++ {
+		_, aErr := strconv.ParseUint(aIDs[i], 10, 64)
+		_, bErr := strconv.ParseUint(bIDs[i], 10, 64)
+		var c int
+		switch {
+		case aErr == nil && bErr == nil:
+			c = compareNumbers(aIDs[i], bIDs[i])
+		case aErr == nil:
+			// Numeric identifiers are lower than alphanumeric ones.
+			c = -1
+		case bErr == nil:
+			c = 1
+		default:
+			c = strings.Compare(aIDs[i], bIDs[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return compareNumbers(strconv.Itoa(len(aIDs)), strconv.Itoa(len(bIDs)))
+}
+
+// compareNumbers compares 2 decimal numbers without leading zeros, whatever their size.
+func compareNumbers(a, b string) int {
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// TagOptions defines how a tag is created by CreateTagWithOptions.
+// Tagger fields are used for annotated tags only, and default to the git user configuration.
+type TagOptions struct {
+	Annotated   bool
+	Message     string
+	TaggerName  string
+	TaggerEmail string
+	TaggerDate  time.Time
+}
+
+// CreateTag creates a tag on HEAD. An annotated tag requires a message.
+func (r *Repo) CreateTag(name, message string, annotated bool) error {
+	return r.CreateTagWithOptions(name, "HEAD", TagOptions{Annotated: annotated, Message: message})
+}
+
+// CreateTagWithOptions creates a tag on ref.
+// Setting the tagger identity or date makes annotated tags reproducible.
+func (r *Repo) CreateTagWithOptions(name, ref string, opts TagOptions) error {
+	if !opts.Annotated {
+		if opts.TaggerName != "" || opts.TaggerEmail != "" || !opts.TaggerDate.IsZero() {
+			return fmt.Errorf("Unable to create the tag '%s'. A tagger requires an annotated tag", name)
+		}
+		if r.Do("tag", name, ref) > 0 {
+			return fmt.Errorf("Unable to create the tag '%s'", name)
+		}
+		return nil
+	}
+
+	// git uses the committer identity as tagger.
+	env := make([]string, 0, 3)
+	if opts.TaggerName != "" {
+		env = append(env, "GIT_COMMITTER_NAME="+opts.TaggerName)
+	}
+	if opts.TaggerEmail != "" {
+		env = append(env, "GIT_COMMITTER_EMAIL="+opts.TaggerEmail)
+	}
+	if !opts.TaggerDate.IsZero() {
+		env = append(env, "GIT_COMMITTER_DATE="+opts.TaggerDate.Format(time.RFC3339))
+	}
+	if r.doWithEnv(env, "tag", "-a", "-m", opts.Message, name, ref) > 0 {
+		return fmt.Errorf("Unable to create the annotated tag '%s'", name)
+	}
+	return nil
+}
+
+// TagsSortedByVersion returns the tags starting with prefix, sorted by version, ie v1.9.0 before v1.10.0.
+// An empty prefix lists all tags.
+func (r *Repo) TagsSortedByVersion(prefix string) ([]string, error) {
+	v, err := r.Get("tag", "--list", "--sort=version:refname", prefix+"*")
+	if err != nil || v == "" {
+		return []string{}, err
+	}
+	return strings.Split(v, "\n"), nil
+}
+
+// NearestTag returns the most recent tag reachable from ref, and the number of commits between this tag and ref.
+// distance is 0 if ref is tagged. ErrNoTags is returned if no tag is reachable.
+func (r *Repo) NearestTag(ref string) (tag string, distance int, err error) {
+	v, err := r.Get("tag", "--list", "--merged", ref)
+	if err != nil {
+		return "", 0, fmt.Errorf("Unable to list the tags reachable from '%s'. %s", ref, err)
+	}
+	if v == "" {
+		return "", 0, ErrNoTags
+	}
+	v, err = r.Get("describe", "--tags", "--long", ref)
+	if err != nil {
+		return "", 0, fmt.Errorf("Unable to describe '%s'. %s", ref, err)
+	}
+
+	// <tag>-<distance>-g<abbreviated sha>
+	describeRE, _ := regexp.Compile(`^(.+)-(\d+)-g[0-9a-f]+$`)
+	m := describeRE.FindStringSubmatch(v)
+	if m == nil {
+		return "", 0, fmt.Errorf("Unable to parse the description '%s'", v)
+	}
+	distance, _ = strconv.Atoi(m[2])
+	return m[1], distance, nil
+}
+
+// TagBehindBranch returns the number of commits of branch not reachable from tag, like the commits since
+// the last release. It returns 0 if the tag is at the tip of branch.
+func (r *Repo) TagBehindBranch(tag, branch string) (int, error) {
+	v, err := r.Get("rev-list", "--count", "refs/tags/"+tag+".."+branch, "--")
+	if err != nil {
+		return 0, fmt.Errorf("Unable to count the commits of '%s' since the tag '%s'. %s", branch, tag, err)
+	}
+	return strconv.Atoi(v)
+}
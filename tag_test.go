@@ -0,0 +1,316 @@
+package git
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreateTagWithOptions(t *testing.T) {
+	t.Log("Expecting CreateTagWithOptions to create reproducible annotated tags.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	date := time.Date(2017, 5, 4, 10, 20, 30, 0, time.UTC)
+
+	// Run the function
+	t.Log("Running CreateTagWithOptions(\"v1.0.0\", \"HEAD\", ...)...")
+	err := CreateTagWithOptions("v1.0.0", "HEAD", TagOptions{
+		Annotated:   true,
+		Message:     "Release 1.0.0",
+		TaggerName:  "Release Bot",
+		TaggerEmail: "bot@example.com",
+		TaggerDate:  date,
+	})
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	expected := "tag Release Bot <bot@example.com> 2017-05-04T10:20:30+00:00 Release 1.0.0"
+	if v := runGit(t, "for-each-ref", "--format=%(objecttype) %(taggername) %(taggeremail) %(taggerdate:iso-strict) %(contents:subject)", "refs/tags/v1.0.0"); v != expected {
+		t.Errorf("Expected '%s'. Got '%s'.", expected, v)
+	}
+
+	// Run the function
+	t.Log("Running CreateTagWithOptions(\"v1.0.1\", ...) with a tagger on a lightweight tag...")
+	err = CreateTagWithOptions("v1.0.1", "HEAD", TagOptions{TaggerName: "Release Bot"})
+
+	// Test the result
+	if err == nil {
+		t.Errorf("Expected an error. Got none.")
+	}
+
+	// Run the function
+	t.Log("Running CreateTag(\"v1.0.2\", \"\", false)...")
+	if err = CreateTag("v1.0.2", "", false); err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+
+	// Test the result
+	if v := runGit(t, "cat-file", "-t", "v1.0.2"); v != "commit" {
+		t.Errorf("Expected a lightweight tag referencing a commit. Got '%s'.", v)
+	}
+}
+
+func TestTagsSortedByVersion(t *testing.T) {
+	t.Log("Expecting TagsSortedByVersion to sort tags by version.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	for _, tag := range []string{"v1.10.0", "v1.2.0", "v1.9.0", "other-1.0"} {
+		runGit(t, "tag", tag)
+	}
+
+	// Run the function
+	t.Log("Running TagsSortedByVersion(\"v\")...")
+	tags, err := TagsSortedByVersion("v")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v := strings.Join(tags, ","); v != "v1.2.0,v1.9.0,v1.10.0" {
+		t.Errorf("Expected 'v1.2.0,v1.9.0,v1.10.0'. Got '%s'.", v)
+	}
+
+	// Run the function
+	t.Log("Running TagsSortedByVersion(\"release-\")...")
+	if tags, err = TagsSortedByVersion("release-"); err != nil {
+		t.Errorf("Expected no error. Got %s.", err)
+	} else if len(tags) != 0 {
+		t.Errorf("Expected no tags. Got %v.", tags)
+	}
+}
+
+func TestNearestTag(t *testing.T) {
+	t.Log("Expecting NearestTag to return the nearest tag and the distance.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+
+	// Run the function
+	t.Log("Running NearestTag(\"HEAD\") without tags...")
+	if _, _, err := NearestTag("HEAD"); err != ErrNoTags {
+		t.Errorf("Expected ErrNoTags. Got %v.", err)
+	}
+
+	runGit(t, "tag", "-a", "-m", "release", "v1.0-rc-1")
+
+	// Run the function
+	t.Log("Running NearestTag(\"HEAD\") on a tagged commit...")
+	tag, distance, err := NearestTag("HEAD")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if tag != "v1.0-rc-1" || distance != 0 {
+		t.Errorf("Expected 'v1.0-rc-1' at distance 0. Got '%s' at %d.", tag, distance)
+	}
+
+	commitFile(t, "file", "2", "second")
+	commitFile(t, "file", "3", "third")
+
+	// Run the function
+	t.Log("Running NearestTag(\"HEAD\") 2 commits after the tag...")
+	tag, distance, err = NearestTag("HEAD")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if tag != "v1.0-rc-1" || distance != 2 {
+		t.Errorf("Expected 'v1.0-rc-1' at distance 2. Got '%s' at %d.", tag, distance)
+	}
+
+	// Run the function
+	t.Log("Running NearestTag(\"unknown\")...")
+	_, _, err = NearestTag("unknown")
+
+	// Test the result
+	if err == nil || err == ErrNoTags {
+		t.Errorf("Expected an error other than ErrNoTags for an unknown ref. Got %v.", err)
+	}
+}
+
+func TestTagBehindBranch(t *testing.T) {
+	t.Log("Expecting TagBehindBranch to count the commits of a branch since a tag.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	runGit(t, "tag", "-a", "-m", "v1", "v1")
+
+	// Run the function
+	t.Log("Running TagBehindBranch(\"v1\", \"master\") with the tag at the tip...")
+	count, err := TagBehindBranch("v1", "master")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected 0 commits. Got %d.", count)
+	}
+
+	commitFile(t, "file", "2", "second")
+	commitFile(t, "file", "3", "third")
+	commitFile(t, "file", "4", "fourth")
+
+	// Run the function
+	t.Log("Running TagBehindBranch(\"v1\", \"master\")...")
+	count, err = TagBehindBranch("v1", "master")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 commits. Got %d.", count)
+	}
+
+	// Run the function
+	t.Log("Running TagBehindBranch(\"unknown\", \"master\")...")
+	if _, err = TagBehindBranch("unknown", "master"); err == nil {
+		t.Errorf("Expected an error. Got none.")
+	}
+}
+
+func TestTags(t *testing.T) {
+	t.Log("Expecting Tags to list lightweight and annotated tags, and DeleteTag to remove them.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	head := runGit(t, "rev-parse", "HEAD")
+	runGit(t, "tag", "light")
+	runGit(t, "tag", "-a", "-m", "Release 1.0\n\nFirst release.", "v1.0")
+
+	// Run the function
+	t.Log("Running Tags()...")
+	tags, err := Tags()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	expected := []Tag{
+		{Name: "light", Target: head},
+		{Name: "v1.0", Annotated: true, Target: head, Message: "Release 1.0\n\nFirst release."},
+	}
+	if !reflect.DeepEqual(tags, expected) {
+		t.Errorf("Expected %+v. Got %+v.", expected, tags)
+	}
+
+	// Run the function
+	t.Log("Running DeleteTag(\"light\")...")
+	err = DeleteTag("light")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v := runGit(t, "tag"); v != "v1.0" {
+		t.Errorf("Expected only 'v1.0' to remain. Got '%s'.", v)
+	}
+	if err = DeleteTag("light"); err == nil {
+		t.Errorf("Expected an error for an unknown tag. Got none.")
+	}
+}
+
+func TestPushTags(t *testing.T) {
+	t.Log("Expecting PushTags to push all tags to the remote.")
+	dir, done := initTestRepo(t)
+	defer done()
+
+	remoteDir := dir + "/remote"
+	runGit(t, "init", "-q", "--bare", remoteDir)
+	runGit(t, "remote", "add", "origin", remoteDir)
+	commitFile(t, "file", "1", "first")
+	runGit(t, "tag", "v1.0")
+	runGit(t, "tag", "-a", "-m", "Release 1.1", "v1.1")
+
+	// Run the function
+	t.Log("Running PushTags(\"\")...")
+	err := PushTags("")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v := runGitIn(t, remoteDir, "tag"); v != "v1.0\nv1.1" {
+		t.Errorf("Expected the tags to be pushed. Got '%s'.", v)
+	}
+}
+
+func TestLatestSemverTag(t *testing.T) {
+	t.Log("Expecting LatestSemverTag to return the highest semantic version.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+
+	// Run the function
+	t.Log("Running LatestSemverTag(\"v\") without tags...")
+	_, err := LatestSemverTag("v")
+
+	// Test the result
+	if err != ErrNoTags {
+		t.Errorf("Expected ErrNoTags. Got %v.", err)
+	}
+
+	for _, tag := range []string{"v1.2.0", "v1.10.0-rc.2", "v1.10.0-rc.10", "v1.9.3", "vnext", "v2.0", "release-3.0.0", "1.0.0"} {
+		runGit(t, "tag", tag)
+	}
+	tests := []struct {
+		prefix, expected string
+	}{
+		{"v", "v1.10.0-rc.10"},
+		{"release-", "release-3.0.0"},
+		{"", "v1.10.0-rc.10"},
+	}
+	for _, test := range tests {
+		// Run the function
+		tag, err := LatestSemverTag(test.prefix)
+
+		// Test the result
+		if err != nil {
+			t.Errorf("Expected no error for '%s'. Got %s.", test.prefix, err)
+		} else if tag != test.expected {
+			t.Errorf("Expected '%s' for '%s'. Got '%s'.", test.expected, test.prefix, tag)
+		}
+	}
+
+	runGit(t, "tag", "v1.10.0")
+
+	// Run the function
+	t.Log("Running LatestSemverTag(\"v\") with a release after its pre-releases...")
+	if tag, _ := LatestSemverTag("v"); tag != "v1.10.0" {
+		t.Errorf("Expected 'v1.10.0'. Got '%s'.", tag)
+	}
+}
+
+func TestCompareSemver(t *testing.T) {
+	t.Log("Expecting compareSemver to follow the semver.org precedence.")
+	ordered := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2",
+		"1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "2.0.0", "10.0.0"}
+	for i := 1; i < len(ordered); i++ {
+		a, b := semverRE.FindStringSubmatch(ordered[i-1]), semverRE.FindStringSubmatch(ordered[i])
+
+		// Run the function
+		lower, higher := compareSemver(a[1:5], b[1:5]), compareSemver(b[1:5], a[1:5])
+
+		// Test the result
+		if lower != -1 || higher != 1 {
+			t.Errorf("Expected %s < %s. Got %d and %d.", ordered[i-1], ordered[i], lower, higher)
+		}
+	}
+	if v := compareSemver([]string{"1", "0", "0", ""}, []string{"1", "0", "0", ""}); v != 0 {
+		t.Errorf("Expected equal versions. Got %d.", v)
+	}
+}
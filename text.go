@@ -0,0 +1,85 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Issues reported by StagedTextIssues.
+const (
+	TextIssueBOM         = "UTF-8 BOM"
+	TextIssueInvalidUTF8 = "invalid UTF-8"
+)
+
+// TextIssue is an encoding issue found in a text file.
+type TextIssue struct {
+	Path string
+	// Issue is TextIssueBOM or TextIssueInvalidUTF8.
+	Issue string
+}
+
+// StagedTextIssues returns the encoding issues of the text files staged, as stored in the index.
+// Only the files which differ from HEAD are checked. Files git considers binary, from their content
+// or the -text attribute, are ignored.
+func (r *Repo) StagedTextIssues() ([]TextIssue, error) {
+	v, err := r.Get("ls-files", "--eol", "-s", "-z")
+	if err != nil {
+		return nil, fmt.Errorf("Unable to list the index entries. %s", err)
+	}
+
+	var staged map[string]bool
+	if _, err := r.Get("rev-parse", "--verify", "-q", "HEAD"); err == nil {
+		entries, err := r.IndexVsTree("HEAD")
+		if err != nil {
+			return nil, err
+		}
+		staged = make(map[string]bool)
+		for _, entry := range entries {
+			staged[entry.Path] = true
+		}
+	}
+
+	issues := make([]TextIssue, 0)
+	for _, line := range strings.Split(v, "\x00") {
+		if line == "" {
+			continue
+		}
+		// <mode> SP <object> SP <stage> TAB i/<eol> SP w/<eol> SP attr/<attributes> TAB <file>
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			return issues, fmt.Errorf("Unable to parse the index entry '%s'", line)
+		}
+		info := strings.Fields(fields[0])
+		if len(info) != 3 {
+			return issues, fmt.Errorf("Unable to parse the index entry '%s'", line)
+		}
+		path := fields[2]
+		// Only regular files are checked, not symlinks nor submodules.
+		if (staged != nil && !staged[path]) || info[2] != "0" || !strings.HasPrefix(info[0], "100") {
+			continue
+		}
+		if strings.HasPrefix(fields[1], "i/-text") || strings.Contains(fields[1], "attr/-text") {
+			continue
+		}
+
+		content, err := r.getRaw("cat-file", "blob", info[1])
+		if err != nil {
+			return issues, fmt.Errorf("Unable to read the staged content of '%s'. %s", path, err)
+		}
+		issues = append(issues, textIssues(path, content)...)
+	}
+	return issues, nil
+}
+
+// textIssues returns the encoding issues of the content of the file path.
+func textIssues(path, content string) []TextIssue {
+	issues := make([]TextIssue, 0)
+	if strings.HasPrefix(content, "\xef\xbb\xbf") {
+		issues = append(issues, TextIssue{Path: path, Issue: TextIssueBOM})
+	}
+	if !utf8.ValidString(content) {
+		issues = append(issues, TextIssue{Path: path, Issue: TextIssueInvalidUTF8})
+	}
+	return issues
+}
@@ -0,0 +1,50 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStagedTextIssues(t *testing.T) {
+	t.Log("Expecting StagedTextIssues to detect BOM and invalid UTF-8 in staged text files.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	writeFile(t, "legacy", "\xef\xbb\xbfcommitted\n")
+	runGit(t, "add", "legacy")
+
+	// Run the function
+	t.Log("Running StagedTextIssues() without commits...")
+	issues, err := StagedTextIssues()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if expected := []TextIssue{{"legacy", TextIssueBOM}}; !reflect.DeepEqual(issues, expected) {
+		t.Errorf("Expected %v. Got %v.", expected, issues)
+	}
+
+	runGit(t, "commit", "-q", "-m", "legacy")
+	writeFile(t, ".gitattributes", "*.dat -text\n")
+	writeFile(t, "bom.txt", "\xef\xbb\xbfhello\n")
+	writeFile(t, "latin1.txt", "caf\xe9\n")
+	writeFile(t, "clean.txt", "café\n")
+	writeFile(t, "binary", "\x00\xef\xbb\xbf\xe9")
+	writeFile(t, "data.dat", "\xef\xbb\xbf\xe9")
+	writeFile(t, "unstaged.txt", "\xef\xbb\xbf")
+	runGit(t, "add", ".gitattributes", "bom.txt", "latin1.txt", "clean.txt", "binary", "data.dat")
+
+	// Run the function
+	t.Log("Running StagedTextIssues()...")
+	issues, err = StagedTextIssues()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	expected := []TextIssue{{"bom.txt", TextIssueBOM}, {"latin1.txt", TextIssueInvalidUTF8}}
+	if !reflect.DeepEqual(issues, expected) {
+		t.Errorf("Expected %v. Got %v.", expected, issues)
+	}
+}
@@ -0,0 +1,69 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// keyIDRE matches a short or long GPG key ID, or a SHA-1 or SHA-256 fingerprint.
+var keyIDRE = regexp.MustCompile(`^([0-9A-F]{8}|[0-9A-F]{16}|[0-9A-F]{40}|[0-9A-F]{64})$`)
+
+// VerifyCommitBy returns true if the commit ref has a good GPG signature made by one of allowedKeyIDs.
+// A key ID can be a long or short key ID, or a fingerprint, of the signing key or of its primary key:
+// 8, 16, 40 or 64 hexadecimal digits, with an optional 0x prefix. An error is returned for any other key ID.
+// An unsigned commit, or a bad or unknown signature returns false without error.
+func (r *Repo) VerifyCommitBy(ref string, allowedKeyIDs []string) (bool, error) {
+	allowedKeys := make([]string, len(allowedKeyIDs))
+	for i, allowed := range allowedKeyIDs {
+		allowedKeys[i] = strings.ToUpper(strings.TrimPrefix(allowed, "0x"))
+		if !keyIDRE.MatchString(allowedKeys[i]) {
+			return false, fmt.Errorf("Invalid key ID '%s'. A key ID or a fingerprint of 8, 16, 40 or 64 hexadecimal digits is expected", allowed)
+		}
+	}
+
+	if _, err := r.Get("rev-parse", "--verify", "-q", ref+"^{commit}"); err != nil {
+		return false, fmt.Errorf("Unable to find the commit '%s'. %s", ref, err)
+	}
+
+	// The GnuPG status lines are written on the error output.
+	out, err := r.combinedOutputCommand(r.command("verify-commit", "--raw", ref))
+	if exitCode(err) < 0 {
+		return false, err
+	}
+	if err != nil {
+		return false, nil
+	}
+
+	signers := signingKeys(string(out))
+	for _, allowed := range allowedKeys {
+		for _, signer := range signers {
+			if strings.HasSuffix(signer, allowed) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// signingKeys returns the key IDs and fingerprints of good signatures from GnuPG status lines.
+func signingKeys(status string) []string {
+	keys := make([]string, 0)
+	for _, line := range strings.Split(status, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[0] != "[GNUPG:]" {
+			continue
+		}
+		switch fields[1] {
+		case "GOODSIG":
+			keys = append(keys, strings.ToUpper(fields[2]))
+		case "VALIDSIG":
+			keys = append(keys, strings.ToUpper(fields[2]))
+			// The last field is the primary key fingerprint.
+			if len(fields) > 11 {
+				keys = append(keys, strings.ToUpper(fields[len(fields)-1]))
+			}
+		}
+	}
+	return keys
+}
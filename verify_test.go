@@ -0,0 +1,114 @@
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestSigningKeys(t *testing.T) {
+	t.Log("Expecting signingKeys to return the keys of good signatures.")
+	status := "[GNUPG:] NEWSIG\n" +
+		"[GNUPG:] GOODSIG 89ABCDEF01234567 Test User <test@example.com>\n" +
+		"[GNUPG:] VALIDSIG 0123456789ABCDEF0123456789ABCDEF01234567 2017-01-01 1483228800 0 4 0 1 8 00 FEDCBA9876543210FEDCBA9876543210FEDCBA98\n"
+
+	// Run the function
+	keys := signingKeys(status)
+
+	// Test the result
+	expected := []string{"89ABCDEF01234567", "0123456789ABCDEF0123456789ABCDEF01234567", "FEDCBA9876543210FEDCBA9876543210FEDCBA98"}
+	if strings.Join(keys, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected %q. Got %q.", expected, keys)
+	}
+	if v := signingKeys("[GNUPG:] BADSIG 89ABCDEF01234567 Test User\n"); len(v) != 0 {
+		t.Errorf("Expected no keys for a bad signature. Got %q.", v)
+	}
+}
+
+func TestVerifyCommitBy(t *testing.T) {
+	t.Log("Expecting VerifyCommitBy to accept only commits signed by an allowed key.")
+	if _, err := exec.LookPath("gpg"); err != nil {
+		t.Skip("gpg is not installed.")
+	}
+	_, done := initTestRepo(t)
+	defer done()
+
+	gnupgHome, err := ioutil.TempDir("", "go-git-gpg")
+	if err != nil {
+		t.Fatalf("Unable to create a temporary directory. %s", err)
+	}
+	defer os.RemoveAll(gnupgHome)
+	os.Setenv("GNUPGHOME", gnupgHome)
+	defer os.Unsetenv("GNUPGHOME")
+	defer exec.Command("gpgconf", "--kill", "gpg-agent").Run()
+
+	newKey := func(email string) string {
+		if out, err := exec.Command("gpg", "--batch", "--passphrase", "", "--quick-gen-key", email, "default", "default", "never").CombinedOutput(); err != nil {
+			t.Skipf("Unable to generate a gpg key. %s\n%s", err, out)
+		}
+		out, _ := exec.Command("gpg", "--with-colons", "--list-secret-keys", email).Output()
+		for _, line := range strings.Split(string(out), "\n") {
+			if fields := strings.Split(line, ":"); fields[0] == "fpr" {
+				return fields[9]
+			}
+		}
+		t.Fatalf("Unable to find the fingerprint of '%s'.", email)
+		return ""
+	}
+	allowed := newKey("allowed@example.com")
+	other := newKey("other@example.com")
+
+	commitFile(t, "file", "1", "unsigned")
+	writeFile(t, "file", "2")
+	runGit(t, "-c", "user.signingkey="+allowed, "commit", "-q", "-S", "-a", "-m", "allowed")
+	writeFile(t, "file", "3")
+	runGit(t, "-c", "user.signingkey="+other, "commit", "-q", "-S", "-a", "-m", "other")
+
+	tests := []struct {
+		ref      string
+		expected bool
+	}{
+		{"HEAD~2", false},
+		{"HEAD~1", true},
+		{"HEAD", false},
+	}
+	for _, test := range tests {
+		// Run the function
+		t.Logf("Running VerifyCommitBy(%q, [allowed])...", test.ref)
+		verified, err := VerifyCommitBy(test.ref, []string{allowed[24:]})
+
+		// Test the result
+		if err != nil {
+			t.Errorf("Expected no error. Got %s.", err)
+		} else if verified != test.expected {
+			t.Errorf("Expected '%s' verified to be %t. Got %t.", test.ref, test.expected, verified)
+		}
+	}
+}
+
+func TestVerifyCommitByInvalidKeyID(t *testing.T) {
+	t.Log("Expecting VerifyCommitBy to reject key IDs which are too short to identify a key.")
+	_, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	for _, keyID := range []string{"A1", "", "0x89ABCDE", "89ABCDEF0123", "not hexadecimal"} {
+		// Run the function
+		_, err := VerifyCommitBy("HEAD", []string{"89ABCDEF01234567", keyID})
+
+		// Test the result
+		if err == nil {
+			t.Errorf("Expected an error for the key ID '%s'. Got none.", keyID)
+		}
+	}
+
+	// Run the function
+	verified, err := VerifyCommitBy("HEAD", []string{"0x89abcdef", "89ABCDEF01234567"})
+
+	// Test the result
+	if err != nil || verified {
+		t.Errorf("Expected an unsigned commit not to be verified, without error. Got %t, %v.", verified, err)
+	}
+}
@@ -0,0 +1,99 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WorktreeAddDetached creates a new worktree in path, with HEAD detached at commit.
+// No branch is created.
+func (r *Repo) WorktreeAddDetached(path, commit string) error {
+	if r.Do("worktree", "add", "--detach", path, commit) > 0 {
+		return fmt.Errorf("Unable to create the worktree '%s' at '%s'", path, commit)
+	}
+	return nil
+}
+
+// WorktreeAdd creates a new worktree in path, with HEAD detached at commit, and returns it as a Repo.
+// Files can then be added and committed in the worktree with the Repo methods, like Add or Commit,
+// without changing the working tree nor the HEAD of the repository.
+func (r *Repo) WorktreeAdd(path, commit string) (*Repo, error) {
+	if err := r.WorktreeAddDetached(path, commit); err != nil {
+		return nil, err
+	}
+	return r.repoAt(r.join(path)), nil
+}
+
+// Worktree is a working tree attached to the repository, as listed by git worktree list.
+type Worktree struct {
+	Path string
+	Head string
+	// Branch is the full name of the branch checked out, like "refs/heads/master", or empty if detached.
+	Branch   string
+	Bare     bool
+	Detached bool
+	// Prunable is true if the worktree directory does not exist anymore.
+	Prunable bool
+}
+
+// WorktreeList returns the worktrees of the repository, the main worktree first.
+func (r *Repo) WorktreeList() ([]Worktree, error) {
+	v, err := r.Get("worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, fmt.Errorf("Unable to list the worktrees. %s", err)
+	}
+
+	worktrees := make([]Worktree, 0)
+	for _, block := range strings.Split(v, "\n\n") {
+		var worktree Worktree
+		for _, line := range strings.Split(block, "\n") {
+			fields := strings.SplitN(line, " ", 2)
+			switch fields[0] {
+			case "worktree":
+				worktree.Path = fields[1]
+			case "HEAD":
+				worktree.Head = fields[1]
+			case "branch":
+				worktree.Branch = fields[1]
+			case "bare":
+				worktree.Bare = true
+			case "detached":
+				worktree.Detached = true
+			case "prunable":
+				worktree.Prunable = true
+			}
+		}
+		if worktree.Path != "" {
+			worktrees = append(worktrees, worktree)
+		}
+	}
+	return worktrees, nil
+}
+
+// WorktreeStatus is the state of a worktree returned by WorktreesStatus.
+type WorktreeStatus struct {
+	Worktree
+	// Dirty is true if the worktree has uncommitted changes or untracked files.
+	Dirty bool
+}
+
+// WorktreesStatus returns the worktrees of the repository with their state.
+// Bare and prunable worktrees are not checked and reported clean.
+func (r *Repo) WorktreesStatus() ([]WorktreeStatus, error) {
+	worktrees, err := r.WorktreeList()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]WorktreeStatus, 0, len(worktrees))
+	for _, worktree := range worktrees {
+		status := WorktreeStatus{Worktree: worktree}
+		if !worktree.Bare && !worktree.Prunable {
+			if status.Dirty, err = r.repoAt(worktree.Path).PathDirty("."); err != nil {
+				return statuses, fmt.Errorf("Unable to get the status of the worktree '%s'. %s", worktree.Path, err)
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
@@ -0,0 +1,122 @@
+package git
+
+import (
+	"path"
+	"testing"
+)
+
+func TestWorktreeAddDetached(t *testing.T) {
+	t.Log("Expecting WorktreeAddDetached to create a worktree on a detached commit.")
+	dir, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	old := runGit(t, "rev-parse", "HEAD")
+	commitFile(t, "file", "2", "second")
+	branches := runGit(t, "branch", "--list")
+	wtDir := dir + ".wt"
+
+	// Run the function
+	t.Logf("Running WorktreeAddDetached(%q, %q)...", wtDir, old)
+	err := WorktreeAddDetached(wtDir, old)
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	defer runGit(t, "worktree", "remove", "--force", wtDir)
+	if v := runGitIn(t, wtDir, "rev-parse", "HEAD"); v != old {
+		t.Errorf("Expected the worktree HEAD to be %s. Got %s.", old, v)
+	}
+	if _, err := Get("-C", wtDir, "symbolic-ref", "-q", "HEAD"); err == nil {
+		t.Errorf("Expected the worktree HEAD to be detached. Got a branch.")
+	}
+	if v := runGit(t, "branch", "--list"); v != branches {
+		t.Errorf("Expected no branch to be created. Got '%s'.", v)
+	}
+}
+
+func TestWorktreeAdd(t *testing.T) {
+	t.Log("Expecting WorktreeAdd to return a worktree to commit in, without changing the main one.")
+	dir, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	head := runGit(t, "rev-parse", "HEAD")
+	wtDir := dir + ".wt"
+
+	// Run the function
+	t.Logf("Running WorktreeAdd(%q, \"HEAD\")...", wtDir)
+	wt, err := WorktreeAdd(wtDir, "HEAD")
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	defer runGit(t, "worktree", "remove", "--force", wtDir)
+	if v := wt.Path(); v != wtDir {
+		t.Errorf("Expected the worktree path to be '%s'. Got '%s'.", wtDir, v)
+	}
+
+	// Run the function
+	t.Log("Running Add() and Commit() in the worktree...")
+	writeFile(t, wtDir+"/new", "1")
+	if v := wt.Add([]string{"new"}); v != 0 {
+		t.Fatalf("Expected Add to return 0. Got %d.", v)
+	}
+	err = wt.Commit("in worktree", true)
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	if v := runGitIn(t, wtDir, "log", "-1", "--format=%s"); v != "in worktree" {
+		t.Errorf("Expected the worktree last commit to be 'in worktree'. Got '%s'.", v)
+	}
+	if v := runGit(t, "rev-parse", "HEAD"); v != head {
+		t.Errorf("Expected the main HEAD to stay %s. Got %s.", head, v)
+	}
+	if v := GetStatus().CountFiles(); v != 0 {
+		t.Errorf("Expected the main working tree to be clean. Got %d files.", v)
+	}
+}
+
+func TestWorktreesStatus(t *testing.T) {
+	t.Log("Expecting WorktreesStatus to report the state of every worktree.")
+	dir, done := initTestRepo(t)
+	defer done()
+
+	commitFile(t, "file", "1", "first")
+	head := runGit(t, "rev-parse", "HEAD")
+	runGit(t, "worktree", "add", "-q", "--detach", dir+".clean")
+	defer runGit(t, "worktree", "remove", "--force", dir+".clean")
+	runGit(t, "worktree", "add", "-q", "-b", "dirty", dir+".dirty")
+	defer runGit(t, "worktree", "remove", "--force", dir+".dirty")
+	writeFile(t, dir+".dirty/file", "2")
+
+	// Run the function
+	t.Log("Running WorktreesStatus()...")
+	statuses, err := WorktreesStatus()
+
+	// Test the result
+	if err != nil {
+		t.Fatalf("Expected no error. Got %s.", err)
+	}
+	expected := map[string]WorktreeStatus{
+		path.Base(dir):            {Worktree: Worktree{Head: head, Branch: "refs/heads/master"}},
+		path.Base(dir) + ".clean": {Worktree: Worktree{Head: head, Detached: true}},
+		path.Base(dir) + ".dirty": {Worktree: Worktree{Head: head, Branch: "refs/heads/dirty"}, Dirty: true},
+	}
+	if v := len(statuses); v != len(expected) {
+		t.Fatalf("Expected %d worktrees. Got %d.", len(expected), v)
+	}
+	for _, status := range statuses {
+		name := path.Base(status.Path)
+		status.Path = ""
+		if v, found := expected[name]; !found {
+			t.Errorf("Unexpected worktree '%s'.", name)
+		} else if v != status {
+			t.Errorf("Expected '%s' to be %+v. Got %+v.", name, v, status)
+		}
+	}
+}